@@ -0,0 +1,8 @@
+package accrualsim
+
+type Config struct {
+	Addr string
+	// Scenario is the path of the JSON scenario file, without it every order is answered with 204.
+	Scenario string
+	LogLevel string
+}
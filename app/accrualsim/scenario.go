@@ -0,0 +1,141 @@
+package accrualsim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/shopspring/decimal"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Scenario scripts the answers of the simulator. Every request for an order plays the next step of its script,
+// the last step is repeated once the script is played through.
+type Scenario struct {
+	// Latency delays every answer on top of the latency of the step.
+	Latency Duration `json:"latency,omitempty"`
+	// RateLimit answers 429 once more requests per minute come in, 0 disables the limit.
+	RateLimit int `json:"rate_limit,omitempty"`
+	// Default is played for orders without a script of their own, without it they are answered with 204.
+	Default []Step `json:"default,omitempty"`
+	// Orders are the scripts by order number.
+	Orders map[string][]Step `json:"orders,omitempty"`
+}
+
+// Step is a single answer, either an order status or an injected error Code.
+type Step struct {
+	Status  dto.OrderStatus  `json:"status,omitempty"`
+	Accrual *decimal.Decimal `json:"accrual,omitempty"`
+	// Code answers with the status code instead, 429 comes with the Retry-After header.
+	Code int `json:"code,omitempty"`
+	// RetryAfter is the Retry-After value of a 429 step in seconds.
+	RetryAfter int      `json:"retry_after,omitempty"`
+	Latency    Duration `json:"latency,omitempty"`
+}
+
+func (s Step) validate() error {
+	if s.Code != 0 {
+		if s.Code < 100 || s.Code > 599 {
+			return fmt.Errorf("invalid status code %d", s.Code)
+		}
+		return nil
+	}
+	switch s.Status {
+	case dto.StatusREGISTERED, dto.StatusPROCESSING, dto.StatusINVALID:
+		if s.Accrual != nil {
+			return fmt.Errorf("accrual is only paid for %s orders", dto.StatusPROCESSED)
+		}
+	case dto.StatusPROCESSED:
+	default:
+		return fmt.Errorf("unknown status %q", s.Status)
+	}
+	return nil
+}
+
+func validateSteps(steps []Step) error {
+	if len(steps) == 0 {
+		return errors.New("script has no steps")
+	}
+	for i, step := range steps {
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks every script of the scenario.
+func (s *Scenario) Validate() error {
+	if s.RateLimit < 0 {
+		return errors.New("rate limit must not be negative")
+	}
+	if len(s.Default) > 0 {
+		if err := validateSteps(s.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for number, steps := range s.Orders {
+		if err := validateSteps(steps); err != nil {
+			return fmt.Errorf("order %s: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// ParseScenario reads and validates a JSON scenario.
+func ParseScenario(r io.Reader) (*Scenario, error) {
+	var scenario Scenario
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, err
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// LoadScenario reads the scenario file, an empty path gives a scenario that answers 204 for every order.
+func LoadScenario(path string) (*Scenario, error) {
+	if path == "" {
+		return &Scenario{}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseScenario(file)
+}
+
+// Duration is a time.Duration written as a string like "250ms" in scenarios.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"250ms\": %w", err)
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// codeText is sent along injected errors, a 429 carries the rate limit text the spec describes.
+func codeText(code int, rateLimit int) string {
+	if code == http.StatusTooManyRequests && rateLimit > 0 {
+		return fmt.Sprintf("No more than %d requests per minute allowed", rateLimit)
+	}
+	return http.StatusText(code)
+}
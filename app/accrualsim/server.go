@@ -0,0 +1,51 @@
+package accrualsim
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"go.uber.org/zap"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type Server struct {
+	Config
+	*Simulator
+	*http.Server
+}
+
+// NewServer loads the scenario file and prepares the simulator to listen on the configured address.
+func NewServer(conf Config) (*Server, error) {
+	scenario, err := LoadScenario(conf.Scenario)
+	if err != nil {
+		return nil, err
+	}
+	simulator := New(scenario)
+	return &Server{
+		Config:    conf,
+		Simulator: simulator,
+		Server: &http.Server{
+			Addr:    conf.Addr,
+			Handler: simulator.Handler(),
+		},
+	}, nil
+}
+
+func (s *Server) AddLogging() error {
+	return logging.Initialize(s.LogLevel)
+}
+
+func (s *Server) Run() error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.Server.Shutdown(timeoutCtx)
+	}()
+	logging.Log.Info("accrual simulator started", zap.String("addr", s.Config.Addr), zap.String("scenario", s.Config.Scenario))
+	return s.ListenAndServe()
+}
@@ -0,0 +1,29 @@
+// Package simtest runs the accrual simulator in tests.
+package simtest
+
+import (
+	"github.com/DimKa163/gophermart/app/accrualsim"
+	"net/http/httptest"
+	"testing"
+)
+
+// Server is a running simulator, URL is the accrual system address to point the client at.
+type Server struct {
+	*httptest.Server
+	*accrualsim.Simulator
+}
+
+// New starts a simulator playing the scenario, it is closed when the test finishes.
+func New(tb testing.TB, scenario *accrualsim.Scenario) *Server {
+	tb.Helper()
+	if scenario == nil {
+		scenario = &accrualsim.Scenario{}
+	}
+	if err := scenario.Validate(); err != nil {
+		tb.Fatalf("invalid accrual scenario: %v", err)
+	}
+	simulator := accrualsim.New(scenario)
+	server := httptest.NewServer(simulator.Handler())
+	tb.Cleanup(server.Close)
+	return &Server{Server: server, Simulator: simulator}
+}
@@ -0,0 +1,242 @@
+package accrualsim
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRetryAfter is sent with 429 steps that set no Retry-After of their own.
+const defaultRetryAfter = 60
+
+// Simulator answers GET /api/orders/{number} like the accrual service according to a scenario
+// that can be changed at runtime, through its methods or the /control endpoints.
+type Simulator struct {
+	mu          sync.Mutex
+	scenario    *Scenario
+	requests    map[string]int
+	windowStart time.Time
+	windowCount int
+}
+
+func New(scenario *Scenario) *Simulator {
+	if scenario == nil {
+		scenario = &Scenario{}
+	}
+	return &Simulator{scenario: scenario, requests: make(map[string]int)}
+}
+
+// Scenario returns the scenario being played.
+func (s *Simulator) Scenario() *Scenario {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scenario
+}
+
+// SetScenario replaces the scenario and starts every script over.
+func (s *Simulator) SetScenario(scenario *Scenario) error {
+	if err := scenario.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenario = scenario
+	s.requests = make(map[string]int)
+	s.windowCount = 0
+	return nil
+}
+
+// SetOrder replaces the script of one order and starts it over.
+func (s *Simulator) SetOrder(number string, steps []Step) error {
+	if err := validateSteps(steps); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scenario := *s.scenario
+	scenario.Orders = make(map[string][]Step, len(s.scenario.Orders)+1)
+	for key, value := range s.scenario.Orders {
+		scenario.Orders[key] = value
+	}
+	scenario.Orders[number] = steps
+	s.scenario = &scenario
+	delete(s.requests, number)
+	return nil
+}
+
+// RemoveOrder drops the script of the order, it is answered by the default script again.
+func (s *Simulator) RemoveOrder(number string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scenario := *s.scenario
+	scenario.Orders = make(map[string][]Step, len(s.scenario.Orders))
+	for key, value := range s.scenario.Orders {
+		if key != number {
+			scenario.Orders[key] = value
+		}
+	}
+	s.scenario = &scenario
+	delete(s.requests, number)
+}
+
+// Reset starts every script over.
+func (s *Simulator) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = make(map[string]int)
+	s.windowCount = 0
+}
+
+// Requests returns how many times the order was asked for since its script started.
+func (s *Simulator) Requests(number string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[number]
+}
+
+// next picks the step answering the request, ok is false for orders without a script.
+func (s *Simulator) next(number string, now time.Time) (step Step, scenario *Scenario, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scenario = s.scenario
+	if scenario.RateLimit > 0 {
+		if now.Sub(s.windowStart) >= time.Minute {
+			s.windowStart, s.windowCount = now, 0
+		}
+		s.windowCount++
+		if s.windowCount > scenario.RateLimit {
+			retryAfter := int(s.windowStart.Add(time.Minute).Sub(now).Seconds()) + 1
+			return Step{Code: http.StatusTooManyRequests, RetryAfter: retryAfter}, scenario, true
+		}
+	}
+	steps, found := scenario.Orders[number]
+	if !found {
+		steps = scenario.Default
+	}
+	if len(steps) == 0 {
+		return Step{}, scenario, false
+	}
+	played := s.requests[number]
+	s.requests[number] = played + 1
+	return steps[min(played, len(steps)-1)], scenario, true
+}
+
+// Handler serves the accrual API and the control endpoints.
+func (s *Simulator) Handler() http.Handler {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.Logging())
+	router.GET("/api/orders/:number", s.Order)
+	control := router.Group("/control")
+	{
+		control.GET("/scenario", s.GetScenario)
+		control.PUT("/scenario", s.PutScenario)
+		control.GET("/orders/:number", s.GetOrder)
+		control.PUT("/orders/:number", s.PutOrder)
+		control.DELETE("/orders/:number", s.DeleteOrder)
+		control.POST("/reset", s.PostReset)
+	}
+	return router
+}
+
+func (s *Simulator) Order(context *gin.Context) {
+	number := context.Param("number")
+	step, scenario, ok := s.next(number, time.Now())
+	latency := time.Duration(scenario.Latency + step.Latency)
+	if latency > 0 {
+		select {
+		case <-context.Request.Context().Done():
+			return
+		case <-time.After(latency):
+		}
+	}
+	logger := logging.Logger(context).With(zap.String("order", number))
+	switch {
+	case !ok:
+		logger.Debug("order is not registered")
+		context.Status(http.StatusNoContent)
+	case step.Code == http.StatusTooManyRequests:
+		retryAfter := step.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = defaultRetryAfter
+		}
+		logger.Debug("answering with 429", zap.Int("retry_after", retryAfter))
+		context.Header("Retry-After", strconv.Itoa(retryAfter))
+		context.String(http.StatusTooManyRequests, codeText(step.Code, scenario.RateLimit))
+	case step.Code == http.StatusNoContent:
+		context.Status(http.StatusNoContent)
+	case step.Code != 0:
+		logger.Debug("injecting error", zap.Int("code", step.Code))
+		context.String(step.Code, codeText(step.Code, scenario.RateLimit))
+	default:
+		order := dto.Order{Number: number, Status: step.Status}
+		if step.Accrual != nil {
+			order.Accrual = &types.Decimal{Decimal: *step.Accrual}
+		}
+		context.JSON(http.StatusOK, &order)
+	}
+}
+
+type orderState struct {
+	Order    string `json:"order"`
+	Requests int    `json:"requests"`
+	Steps    []Step `json:"steps,omitempty"`
+}
+
+func (s *Simulator) GetScenario(context *gin.Context) {
+	context.JSON(http.StatusOK, s.Scenario())
+}
+
+// PutScenario replaces the whole scenario and starts every script over.
+func (s *Simulator) PutScenario(context *gin.Context) {
+	scenario, err := ParseScenario(context.Request.Body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err = s.SetScenario(scenario); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (s *Simulator) GetOrder(context *gin.Context) {
+	number := context.Param("number")
+	context.JSON(http.StatusOK, &orderState{
+		Order:    number,
+		Requests: s.Requests(number),
+		Steps:    s.Scenario().Orders[number],
+	})
+}
+
+// PutOrder replaces the script of one order, the body is the list of steps.
+func (s *Simulator) PutOrder(context *gin.Context) {
+	var steps []Step
+	if err := context.ShouldBindJSON(&steps); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.SetOrder(context.Param("number"), steps); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
+
+func (s *Simulator) DeleteOrder(context *gin.Context) {
+	s.RemoveOrder(context.Param("number"))
+	context.Status(http.StatusNoContent)
+}
+
+func (s *Simulator) PostReset(context *gin.Context) {
+	s.Reset()
+	context.Status(http.StatusNoContent)
+}
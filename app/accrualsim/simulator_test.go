@@ -0,0 +1,93 @@
+package accrualsim_test
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/app/accrualsim"
+	"github.com/DimKa163/gophermart/app/accrualsim/simtest"
+	"github.com/DimKa163/gophermart/internal/shared/tripper"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newClient(sim *simtest.Server) accrual.AccrualClient {
+	return accrual.New(sim.URL, 0, tripper.NewRateLimiter(0), nil)
+}
+
+func TestSimulatorShouldPlayOrderScript(t *testing.T) {
+	accrued := decimal.NewFromFloat(729.98)
+	sim := simtest.New(t, &accrualsim.Scenario{
+		Orders: map[string][]accrualsim.Step{
+			"12345678903": {
+				{Status: dto.StatusREGISTERED},
+				{Code: http.StatusTooManyRequests, RetryAfter: 5},
+				{Code: http.StatusInternalServerError},
+				{Status: dto.StatusPROCESSED, Accrual: &accrued},
+			},
+		},
+	})
+	client := newClient(sim)
+	ctx := context.Background()
+
+	order, err := client.Order(ctx, "12345678903")
+	assert.NoError(t, err)
+	assert.Equal(t, dto.StatusREGISTERED, order.Status)
+
+	_, err = client.Order(ctx, "12345678903")
+	var throttled *accrual.TooManyRequestsError
+	assert.ErrorAs(t, err, &throttled)
+
+	_, err = client.Order(ctx, "12345678903")
+	var status *accrual.StatusError
+	if assert.ErrorAs(t, err, &status) {
+		assert.Equal(t, http.StatusInternalServerError, status.Code)
+	}
+
+	for i := 0; i < 2; i++ {
+		order, err = client.Order(ctx, "12345678903")
+		assert.NoError(t, err)
+		assert.Equal(t, "12345678903", order.Number)
+		assert.Equal(t, dto.StatusPROCESSED, order.Status)
+		assert.True(t, accrued.Equal(order.Accrual.Decimal))
+	}
+	assert.Equal(t, 5, sim.Requests("12345678903"))
+
+	_, err = client.Order(ctx, "79927398713")
+	assert.ErrorIs(t, err, accrual.ErrNoContent)
+}
+
+func TestSimulatorShouldChangeScriptsAtRuntime(t *testing.T) {
+	sim := simtest.New(t, nil)
+	client := newClient(sim)
+
+	request, err := http.NewRequest(http.MethodPut, sim.URL+"/control/orders/79927398713",
+		strings.NewReader(`[{"status": "INVALID"}]`))
+	assert.NoError(t, err)
+	response, err := sim.Client().Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	order, err := client.Order(context.Background(), "79927398713")
+	assert.NoError(t, err)
+	assert.Equal(t, dto.StatusINVALID, order.Status)
+
+	sim.RemoveOrder("79927398713")
+	_, err = client.Order(context.Background(), "79927398713")
+	assert.ErrorIs(t, err, accrual.ErrNoContent)
+}
+
+func TestSimulatorShouldRejectInvalidScenario(t *testing.T) {
+	_, err := accrualsim.ParseScenario(strings.NewReader(`{"orders": {"1": [{"status": "DONE"}]}}`))
+	assert.Error(t, err)
+
+	_, err = accrualsim.ParseScenario(strings.NewReader(`{"orders": {"1": [{"status": "PROCESSING", "accrual": 5}]}}`))
+	assert.Error(t, err)
+
+	_, err = accrualsim.LoadScenario("../../cmd/accrual-sim/scenario.example.json")
+	assert.NoError(t, err)
+}
@@ -1,6 +1,9 @@
 package gophermart
 
-import "github.com/DimKa163/gophermart/internal/shared/auth"
+import (
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"time"
+)
 
 type Config struct {
 	Addr         string
@@ -9,5 +12,93 @@ type Config struct {
 	Secret       string
 	LogLevel     string
 	CronSchedule string
+	AdminKey     string
+	PartnerKey   string
 	Argon        auth.ArgonConfig
+	Expiration   ExpirationConfig
+	Transfer     TransferConfig
+	Export       ExportConfig
+	Withdrawal   WithdrawalConfig
+	Tracking     TrackingConfig
+	Breaker      BreakerConfig
+	Callback     CallbackConfig
+	Providers    ProvidersConfig
+	// ShutdownTimeout bounds the graceful shutdown, a tracking cycle still running then is cancelled.
+	ShutdownTimeout time.Duration
+	// SnapshotSchedule is when balance snapshots for point-in-time queries are taken.
+	SnapshotSchedule string
+}
+
+type ExpirationConfig struct {
+	Months   uint
+	Notice   time.Duration
+	Schedule string
+	DryRun   bool
+}
+
+type TransferConfig struct {
+	DailyAmount float64
+	DailyCount  uint
+}
+
+type ExportConfig struct {
+	Dir      string
+	TTL      time.Duration
+	Schedule string
+}
+
+type WithdrawalConfig struct {
+	MinAmount     float64
+	MaxAmount     float64
+	DailyCap      float64
+	MonthlyCap    float64
+	MaxOrderShare float64
+	CoolingOff    time.Duration
+}
+
+type TrackingConfig struct {
+	BatchSize   uint
+	Concurrency uint
+	// RateLimit caps accrual requests per second, 0 disables the limiter.
+	RateLimit          float64
+	RegisteredInterval time.Duration
+	ProcessingInterval time.Duration
+	ErrorBackoff       time.Duration
+	MaxBackoff         time.Duration
+	LeaseTTL           time.Duration
+	// MaxAttempts dead-letters an order after that many failed checks in a row, 0 retries forever.
+	MaxAttempts uint
+	// MaxAccrual is the largest accrual accepted for a single order, 0 only keeps the storage limit.
+	MaxAccrual float64
+	// ElectionInterval is how often followers try to take over and the leader checks its lock.
+	ElectionInterval time.Duration
+	// History is how many of the latest tracking cycles the status shows.
+	History uint
+	// NotifyDebounce coalesces upload notifications into one tracking cycle.
+	NotifyDebounce time.Duration
+}
+
+type ProvidersConfig struct {
+	// List adds named accrual services next to the default one, see ParseProviders.
+	List string
+	// Routes send orders to providers by number prefix, see ParseRoutes.
+	Routes string
+	// Timeout bounds a request to the default provider and to providers without a timeout of their own.
+	Timeout time.Duration
+}
+
+// CallbackConfig secures results pushed by the accrual service, an empty secret turns callbacks off.
+type CallbackConfig struct {
+	Secret string
+	// Tolerance is how far the signature timestamp may be off, older callbacks are rejected as replays.
+	Tolerance time.Duration
+}
+
+type BreakerConfig struct {
+	ConsecutiveFailures uint
+	ErrorRate           float64
+	MinRequests         uint
+	Window              time.Duration
+	OpenTimeout         time.Duration
+	HalfOpenProbes      uint
 }
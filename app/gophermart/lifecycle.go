@@ -0,0 +1,95 @@
+package gophermart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Component is a part of the service with its own start and stop step, both are optional.
+type Component struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Lifecycle starts components in the order they were added and stops the started ones in reverse,
+// so a component is only stopped after everything depending on it.
+type Lifecycle struct {
+	components []Component
+	started    []Component
+}
+
+func (l *Lifecycle) Append(components ...Component) {
+	l.components = append(l.components, components...)
+}
+
+// Start stops at the first component that fails, the ones started before it are left for Stop.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	logger := logging.Logger(ctx)
+	for _, component := range l.components {
+		logger.Info("starting component", zap.String("component", component.Name))
+		if component.Start != nil {
+			if err := component.Start(ctx); err != nil {
+				return fmt.Errorf("start %s: %w", component.Name, err)
+			}
+		}
+		l.started = append(l.started, component)
+	}
+	return nil
+}
+
+// Stop stops every started component, even when one of them fails. All of them share the ctx deadline.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	logger := logging.Logger(ctx)
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		component := l.started[i]
+		if component.Stop == nil {
+			continue
+		}
+		logger.Info("stopping component", zap.String("component", component.Name))
+		start := time.Now()
+		if err := component.Stop(ctx); err != nil {
+			logger.Warn("failed to stop component", zap.String("component", component.Name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, err))
+			continue
+		}
+		logger.Info("component stopped", zap.String("component", component.Name),
+			zap.Duration("took", time.Since(start)))
+	}
+	l.started = nil
+	return errors.Join(errs...)
+}
+
+// Background runs fn in a goroutine until the component is stopped, fn must return once its ctx is done.
+func Background(name string, fn func(ctx context.Context)) Component {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			ctx, cancel = context.WithCancel(ctx)
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				fn(ctx)
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
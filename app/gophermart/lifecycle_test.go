@@ -0,0 +1,80 @@
+package gophermart_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/app/gophermart"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func recorded(calls *[]string, name string, err error) gophermart.Component {
+	return gophermart.Component{
+		Name: name,
+		Start: func(context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return err
+		},
+		Stop: func(context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycleShouldStopInReverseOrder(t *testing.T) {
+	var calls []string
+	var lifecycle gophermart.Lifecycle
+	lifecycle.Append(recorded(&calls, "database", nil), recorded(&calls, "worker", nil), recorded(&calls, "http", nil))
+
+	assert.NoError(t, lifecycle.Start(context.Background()))
+	assert.NoError(t, lifecycle.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start database", "start worker", "start http",
+		"stop http", "stop worker", "stop database",
+	}, calls)
+}
+
+func TestLifecycleShouldOnlyStopStartedComponents(t *testing.T) {
+	var calls []string
+	failure := errors.New("port in use")
+	var lifecycle gophermart.Lifecycle
+	lifecycle.Append(recorded(&calls, "database", nil), recorded(&calls, "http", failure), recorded(&calls, "metrics", nil))
+
+	assert.ErrorIs(t, lifecycle.Start(context.Background()), failure)
+	assert.NoError(t, lifecycle.Stop(context.Background()))
+
+	assert.Equal(t, []string{"start database", "start http", "stop database"}, calls)
+}
+
+func TestLifecycleShouldStopEveryComponentWhenOneFails(t *testing.T) {
+	var calls []string
+	failure := errors.New("flush failed")
+	broken := recorded(&calls, "export", nil)
+	broken.Stop = func(context.Context) error {
+		calls = append(calls, "stop export")
+		return failure
+	}
+	var lifecycle gophermart.Lifecycle
+	lifecycle.Append(recorded(&calls, "database", nil), broken, recorded(&calls, "http", nil))
+
+	assert.NoError(t, lifecycle.Start(context.Background()))
+	assert.ErrorIs(t, lifecycle.Stop(context.Background()), failure)
+
+	assert.Equal(t, []string{"stop http", "stop export", "stop database"}, calls[3:])
+}
+
+func TestBackgroundShouldCancelOnStop(t *testing.T) {
+	var lifecycle gophermart.Lifecycle
+	cancelled := false
+	lifecycle.Append(gophermart.Background("listener", func(ctx context.Context) {
+		<-ctx.Done()
+		cancelled = true
+	}))
+
+	assert.NoError(t, lifecycle.Start(context.Background()))
+	assert.NoError(t, lifecycle.Stop(context.Background()))
+
+	assert.True(t, cancelled)
+}
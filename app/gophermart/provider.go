@@ -0,0 +1,80 @@
+package gophermart
+
+import (
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProviderConfig describes an accrual service besides the default one.
+type ProviderConfig struct {
+	Name    string
+	Addr    string
+	Timeout time.Duration
+	// RateLimit caps requests per second, 0 disables the limiter.
+	RateLimit float64
+}
+
+// ParseProviders reads name=address entries separated by commas, an entry may set its own timeout
+// and rate limit after semicolons, e.g. "brand-a=http://a:8080;timeout=10s;rate=5,brand-b=http://b:8080".
+// Entries without them take the given defaults.
+func ParseProviders(value string, timeout time.Duration, rate float64) ([]ProviderConfig, error) {
+	var providers []ProviderConfig
+	seen := map[string]bool{model.DefaultProvider: true}
+	for _, entry := range splitList(value) {
+		options := strings.Split(entry, ";")
+		name, addr, ok := strings.Cut(options[0], "=")
+		name, addr = strings.TrimSpace(name), strings.TrimSpace(addr)
+		if !ok || name == "" || addr == "" {
+			return nil, fmt.Errorf("accrual provider %q: expected name=address", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("accrual provider %q is configured twice", name)
+		}
+		seen[name] = true
+		provider := ProviderConfig{Name: name, Addr: addr, Timeout: timeout, RateLimit: rate}
+		for _, option := range options[1:] {
+			key, raw, _ := strings.Cut(strings.TrimSpace(option), "=")
+			var err error
+			switch key {
+			case "timeout":
+				provider.Timeout, err = time.ParseDuration(raw)
+			case "rate":
+				provider.RateLimit, err = strconv.ParseFloat(raw, 64)
+			default:
+				err = fmt.Errorf("unknown option %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("accrual provider %q: %w", name, err)
+			}
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// ParseRoutes reads prefix=provider rules separated by commas, e.g. "2377=brand-a,4=brand-b".
+func ParseRoutes(value string) ([]model.ProviderRoute, error) {
+	var routes []model.ProviderRoute
+	for _, entry := range splitList(value) {
+		prefix, provider, ok := strings.Cut(entry, "=")
+		prefix, provider = strings.TrimSpace(prefix), strings.TrimSpace(provider)
+		if _, err := strconv.ParseUint(prefix, 10, 64); !ok || err != nil || provider == "" {
+			return nil, fmt.Errorf("accrual route %q: expected number prefix=provider", entry)
+		}
+		routes = append(routes, model.ProviderRoute{Prefix: prefix, Provider: provider})
+	}
+	return routes, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
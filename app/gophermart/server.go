@@ -2,34 +2,64 @@ package gophermart
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"github.com/DimKa163/gophermart/internal/shared/auth"
 	"github.com/DimKa163/gophermart/internal/shared/db"
 	"github.com/DimKa163/gophermart/internal/shared/logging"
 	"github.com/DimKa163/gophermart/internal/shared/tripper"
+	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/uow"
 	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual"
 	"github.com/DimKa163/gophermart/internal/user/infrastructure/persistence"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/storage"
 	"github.com/DimKa163/gophermart/internal/user/interfaces/middleware"
 	"github.com/DimKa163/gophermart/internal/user/interfaces/rest"
 	"github.com/DimKa163/gophermart/internal/user/interfaces/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/robfig/cron/v3"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"net"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 )
 
+// trackingLockKey is the advisory lock held by the instance that tracks orders.
+const trackingLockKey int64 = 7_341_000_001
+
 type ServiceContainer struct {
-	userAPI     rest.UserAPI
-	authService auth.AuthService
-	unitOfWork  uow.UnitOfWork
-	pgPool      *pgxpool.Pool
-	worker      *worker.OrderPooler
-	crn         *cron.Cron
-	accrualCl   accrual.AccrualClient
+	userAPI       rest.UserAPI
+	adminAPI      rest.AdminAPI
+	partnerAPI    rest.PartnerAPI
+	transferAPI   rest.TransferAPI
+	promoAPI      rest.PromoAPI
+	exportAPI     rest.ExportAPI
+	withdrawalAPI rest.WithdrawalAPI
+	accrualAPI    rest.AccrualAPI
+	deadLetterAPI rest.DeadLetterAPI
+	quarantineAPI rest.QuarantineAPI
+	callbackAPI   rest.CallbackAPI
+	trackingAPI   rest.TrackingAPI
+	authService   auth.AuthService
+	unitOfWork    uow.UnitOfWork
+	pgPool        *pgxpool.Pool
+	worker        *worker.OrderPooler
+	expiration    *worker.ExpirationJob
+	export        *worker.ExportJob
+	snapshot      *worker.SnapshotJob
+	crn           *cron.Cron
+	leader        *db.LeaderElector
+	notifier      *worker.UploadNotifier
+	instance      string
+	accrualCl     accrual.AccrualClient
 }
 type Server struct {
 	Config
@@ -60,16 +90,82 @@ func (s *Server) AddServices() error {
 	}
 	s.authService = s.addAuthService()
 	s.unitOfWork = addUnitOfWork(s.pgPool, attempts)
+	expirationService := application.NewExpirationService(s.unitOfWork, model.ExpirationPolicy{
+		Months: int(s.Expiration.Months),
+		Notice: s.Expiration.Notice,
+	}, 100)
+	withdrawalPolicy := application.NewWithdrawalPolicy(s.unitOfWork, model.WithdrawalRules{
+		MinAmount:     types.Decimal{Decimal: decimal.NewFromFloat(s.Withdrawal.MinAmount)},
+		MaxAmount:     types.Decimal{Decimal: decimal.NewFromFloat(s.Withdrawal.MaxAmount)},
+		DailyCap:      types.Decimal{Decimal: decimal.NewFromFloat(s.Withdrawal.DailyCap)},
+		MonthlyCap:    types.Decimal{Decimal: decimal.NewFromFloat(s.Withdrawal.MonthlyCap)},
+		MaxOrderShare: types.Decimal{Decimal: decimal.NewFromFloat(s.Withdrawal.MaxOrderShare)},
+		CoolingOff:    s.Withdrawal.CoolingOff,
+	})
+	s.withdrawalAPI = rest.NewWithdrawalAPI(withdrawalPolicy)
+	providers, routing, breakers, err := s.addProviders()
+	if err != nil {
+		return err
+	}
 	s.userAPI = rest.NewUserAPI(application.NewUserService(s.unitOfWork, s.authService),
-		application.NewOrderService(s.unitOfWork))
-	accrualCl := addAccrualClient(s.Accrual)
+		application.NewOrderService(s.unitOfWork, withdrawalPolicy, routing), expirationService, application.NewTierService(s.unitOfWork))
+	transactionService := application.NewTransactionService(s.unitOfWork)
+	balanceService := application.NewBalanceService(s.unitOfWork)
+	s.adminAPI = rest.NewAdminAPI(transactionService, expirationService, balanceService)
+	s.partnerAPI = rest.NewPartnerAPI(transactionService)
+	s.transferAPI = rest.NewTransferAPI(application.NewTransferService(s.unitOfWork, model.TransferLimits{
+		DailyAmount: types.Decimal{Decimal: decimal.NewFromFloat(s.Transfer.DailyAmount)},
+		DailyCount:  int(s.Transfer.DailyCount),
+	}))
+	s.promoAPI = rest.NewPromoAPI(application.NewPromoService(s.unitOfWork))
+	exportStorage, err := storage.NewExportStorage(s.Export.Dir)
+	if err != nil {
+		return err
+	}
+	exportService := application.NewExportService(s.unitOfWork, exportStorage, s.Export.TTL)
+	s.exportAPI = rest.NewExportAPI(exportService)
+	s.instance = instanceName()
+	s.leader = db.NewLeaderElector(s.pgPool, trackingLockKey, s.instance, s.Tracking.ElectionInterval)
+	s.accrualAPI = rest.NewAccrualAPI(breakers, s.leader)
+	tiers := application.NewTierEvaluator()
+	policy := model.TrackingPolicy{
+		Registered:  s.Tracking.RegisteredInterval,
+		Processing:  s.Tracking.ProcessingInterval,
+		ErrorBase:   s.Tracking.ErrorBackoff,
+		ErrorMax:    s.Tracking.MaxBackoff,
+		Lease:       s.Tracking.LeaseTTL,
+		MaxAttempts: int(s.Tracking.MaxAttempts),
+		MaxAccrual:  types.Decimal{Decimal: decimal.NewFromFloat(s.Tracking.MaxAccrual)},
+	}
+	s.callbackAPI = rest.NewCallbackAPI(application.NewAccrualCallbackService(s.unitOfWork, tiers, policy))
+	s.deadLetterAPI = rest.NewDeadLetterAPI(application.NewDeadLetterService(s.unitOfWork, tiers))
+	s.quarantineAPI = rest.NewQuarantineAPI(application.NewQuarantineService(s.unitOfWork))
 	s.crn = cron.New(cron.WithSeconds(),
 		cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
 	s.worker, err = worker.NewWorker(s.crn,
-		s.CronSchedule, 10, application.NewTrackOrderHandler(s.unitOfWork, application.NewTrackOrderProcessor(accrualCl)))
+		s.CronSchedule, int(s.Tracking.BatchSize), int(s.Tracking.History), application.NewTrackOrderHandler(s.unitOfWork,
+			application.NewTrackOrderProcessor(providers, int(s.Tracking.Concurrency), policy.MaxAccrual),
+			tiers, policy, s.instance), breakers, s.leader)
 	if err != nil {
 		return err
 	}
+	s.trackingAPI = rest.NewTrackingAPI(s.worker)
+	s.notifier = worker.NewUploadNotifier(db.NewListener(s.pgPool, persistence.OrderUploadedChannel), s.worker,
+		s.Tracking.NotifyDebounce)
+	s.export, err = worker.NewExportJob(s.crn, s.Export.Schedule, exportService)
+	if err != nil {
+		return err
+	}
+	s.snapshot, err = worker.NewSnapshotJob(s.crn, s.SnapshotSchedule, balanceService)
+	if err != nil {
+		return err
+	}
+	if s.Expiration.Months > 0 {
+		s.expiration, err = worker.NewExpirationJob(s.crn, s.Expiration.Schedule, s.Expiration.DryRun, expirationService)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -90,33 +186,148 @@ func (s *Server) Map() {
 		{
 			userGroup.GET("/orders", userAPI.GetOrders)
 			userGroup.GET("/withdrawals", userAPI.GetWithdrawals)
+			userGroup.GET("/transactions", userAPI.GetTransactions)
+			userGroup.GET("/tier", userAPI.GetTier)
+			userGroup.GET("/tier/history", userAPI.GetTierHistory)
+			userGroup.POST("/exports", s.exportAPI.Create)
+			userGroup.GET("/exports/:id", s.exportAPI.Get)
+			userGroup.GET("/exports/:id/download", s.exportAPI.Download)
 			userGroup.POST("/orders", userAPI.Upload)
 			balanceGroup := userGroup.Group("/balance")
 			{
 				balanceGroup.GET("", userAPI.GetBalance)
+				balanceGroup.GET("/statement", userAPI.GetStatement)
 				balanceGroup.POST("/withdraw", userAPI.Withdraw)
+				balanceGroup.GET("/withdrawal-rules", s.withdrawalAPI.GetRules)
+				balanceGroup.POST("/transfer", s.transferAPI.Transfer)
+				balanceGroup.POST("/redeem-code", s.promoAPI.Redeem)
 			}
 		}
 	}
+	adminGroup := s.Group("api/admin")
+	{
+		adminAPI := s.adminAPI
+		adminGroup.Use(middleware.APIKey(s.AdminKey))
+		adminGroup.POST("/transactions/:id/reverse", adminAPI.Reverse)
+		adminGroup.POST("/expirations", adminAPI.Expire)
+		adminGroup.GET("/users/:id/balance", adminAPI.Balance)
+		adminGroup.GET("/withdrawal-rules", s.withdrawalAPI.ListOverrides)
+		adminGroup.PUT("/withdrawal-rules", s.withdrawalAPI.SaveOverride)
+		adminGroup.DELETE("/withdrawal-rules/:id", s.withdrawalAPI.DeleteOverride)
+		adminGroup.GET("/promo-codes", s.promoAPI.List)
+		adminGroup.POST("/promo-codes", s.promoAPI.Create)
+		adminGroup.POST("/promo-codes/gift", s.promoAPI.Generate)
+		adminGroup.GET("/accrual/status", s.accrualAPI.Status)
+		adminGroup.GET("/tracking", s.trackingAPI.Status)
+		adminGroup.POST("/tracking/pause", s.trackingAPI.Pause)
+		adminGroup.POST("/tracking/resume", s.trackingAPI.Resume)
+		adminGroup.POST("/tracking/trigger", s.trackingAPI.Trigger)
+		adminGroup.PUT("/tracking/settings", s.trackingAPI.Settings)
+		adminGroup.GET("/metrics", gin.WrapH(expvar.Handler()))
+		adminGroup.GET("/dead-letters", s.deadLetterAPI.List)
+		adminGroup.GET("/dead-letters/:number", s.deadLetterAPI.Get)
+		adminGroup.POST("/dead-letters/:number/requeue", s.deadLetterAPI.Requeue)
+		adminGroup.POST("/dead-letters/:number/resolve", s.deadLetterAPI.Resolve)
+		adminGroup.GET("/quarantine", s.quarantineAPI.List)
+		adminGroup.GET("/quarantine/:id", s.quarantineAPI.Get)
+		adminGroup.POST("/quarantine/:id/review", s.quarantineAPI.Review)
+	}
+	internalGroup := s.Group("api/internal")
+	{
+		internalGroup.Use(middleware.Signature(s.Callback.Secret, s.Callback.Tolerance))
+		internalGroup.POST("/accrual/callback", s.callbackAPI.Accrual)
+	}
+	partnerGroup := s.Group("api/partner")
+	{
+		partnerAPI := s.partnerAPI
+		partnerGroup.Use(middleware.APIKey(s.PartnerKey))
+		partnerGroup.POST("/orders/:number/refund", partnerAPI.Refund)
+		partnerGroup.POST("/orders/:number/reversal", partnerAPI.Reverse)
+	}
 }
 
+// Run starts the service and blocks until SIGINT or SIGTERM, then stops it within the shutdown timeout:
+// HTTP traffic first, then the background work, the database pool last.
 func (s *Server) Run() error {
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
-	if err := persistence.Migrate(s.pgPool); err != nil {
-		return err
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	logger := logging.Logger(ctx)
+	serveErr := make(chan error, 1)
+	lifecycle := s.lifecycle(serveErr)
+	// components outlive the signal, each of them is stopped in turn by the lifecycle
+	err := lifecycle.Start(context.WithoutCancel(ctx))
+	if err == nil {
+		select {
+		case <-ctx.Done():
+			logger.Info("shutdown signal received", zap.Duration("timeout", s.ShutdownTimeout))
+		case err = <-serveErr:
+			logger.Error("http server failed", zap.Error(err))
+		}
 	}
-	s.crn.Start()
-	if err := s.worker.Run(ctx); err != nil {
-		return err
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+	err = errors.Join(err, lifecycle.Stop(timeoutCtx))
+	if err == nil {
+		logger.Info("server stopped")
 	}
-	go func() {
-		<-ctx.Done()
-		timeoutCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer cancel()
-		_ = s.Server.Shutdown(timeoutCtx)
-	}()
-	return s.ListenAndServe()
+	return err
+}
+
+// lifecycle lists the components in dependency order, serve errors of the http server are sent to serveErr.
+func (s *Server) lifecycle(serveErr chan<- error) *Lifecycle {
+	var lifecycle Lifecycle
+	lifecycle.Append(
+		Component{
+			Name: "database",
+			Start: func(context.Context) error {
+				return persistence.Migrate(s.pgPool, "file://migrations")
+			},
+			Stop: func(context.Context) error {
+				s.pgPool.Close()
+				return nil
+			},
+		},
+		Background("leader election", s.leader.Run),
+		Component{
+			Name:  "order tracking",
+			Start: s.worker.Run,
+			Stop:  s.worker.Stop,
+		},
+		Component{
+			Name: "scheduler",
+			Start: func(context.Context) error {
+				s.crn.Start()
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				// waits for the running jobs
+				select {
+				case <-s.crn.Stop().Done():
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			},
+		},
+		Background("upload notifications", s.notifier.Run),
+		Component{
+			Name: "http server",
+			Start: func(context.Context) error {
+				listener, err := net.Listen("tcp", s.Server.Addr)
+				if err != nil {
+					return err
+				}
+				go func() {
+					if err := s.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+						serveErr <- err
+					}
+				}()
+				return nil
+			},
+			Stop: s.Shutdown,
+		},
+	)
+	return &lifecycle
 }
 
 func addPgPool(database string) (*pgxpool.Pool, error) {
@@ -140,14 +351,74 @@ func addUnitOfWork(qe db.QueryExecutor, attempts []int) uow.UnitOfWork {
 	return persistence.NewUnitOfWork(qe, db.NewRetryStrategy(attempts))
 }
 
-func addAccrualClient(addr string) accrual.AccrualClient {
+// instanceName identifies this process among the instances sharing the database.
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "gophermart"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// addProviders creates a client with its own rate limiter and circuit breaker for the default accrual service
+// and every additional provider, and checks that the routes lead to configured providers.
+func (s *Server) addProviders() (*accrual.Providers, model.ProviderRouting, []*tripper.CircuitBreaker, error) {
+	var routing model.ProviderRouting
+	configs, err := ParseProviders(s.Providers.List, s.Providers.Timeout, s.Tracking.RateLimit)
+	if err != nil {
+		return nil, routing, nil, err
+	}
+	if routing.Routes, err = ParseRoutes(s.Providers.Routes); err != nil {
+		return nil, routing, nil, err
+	}
+	configs = append([]ProviderConfig{{
+		Name:      model.DefaultProvider,
+		Addr:      s.Accrual,
+		Timeout:   s.Providers.Timeout,
+		RateLimit: s.Tracking.RateLimit,
+	}}, configs...)
+	clients := make(map[string]accrual.AccrualClient, len(configs))
+	breakers := make([]*tripper.CircuitBreaker, len(configs))
+	for i, config := range configs {
+		breakers[i] = tripper.NewCircuitBreaker(config.Name, tripper.BreakerConfig{
+			ConsecutiveFailures: int(s.Breaker.ConsecutiveFailures),
+			ErrorRate:           s.Breaker.ErrorRate,
+			MinRequests:         int(s.Breaker.MinRequests),
+			Window:              s.Breaker.Window,
+			OpenTimeout:         s.Breaker.OpenTimeout,
+			HalfOpenProbes:      int(s.Breaker.HalfOpenProbes),
+		})
+		clients[config.Name] = addAccrualClient(config.Addr, config.Timeout, tripper.NewRateLimiter(config.RateLimit), breakers[i])
+		if config.Name != model.DefaultProvider {
+			routing.Providers = append(routing.Providers, config.Name)
+		}
+	}
+	for _, route := range routing.Routes {
+		if !routing.Known(route.Provider) {
+			return nil, routing, nil, fmt.Errorf("accrual route %s=%s: %w", route.Prefix, route.Provider, model.ErrUnknownProvider)
+		}
+	}
+	providers, err := accrual.NewProviders(clients)
+	if err != nil {
+		return nil, routing, nil, err
+	}
+	return providers, routing, breakers, nil
+}
+
+func addAccrualClient(addr string, timeout time.Duration, limiter *tripper.RateLimiter, breaker *tripper.CircuitBreaker) accrual.AccrualClient {
 	tripperFc := []func(transport http.RoundTripper) http.RoundTripper{
+		func(transport http.RoundTripper) http.RoundTripper {
+			return tripper.NewRateLimitRoundTripper(transport, limiter)
+		},
 		func(transport http.RoundTripper) http.RoundTripper {
 			return tripper.NewRetryRoundTripper(transport)
 		},
+		func(transport http.RoundTripper) http.RoundTripper {
+			return tripper.NewCircuitBreakerRoundTripper(transport, breaker)
+		},
 		func(transport http.RoundTripper) http.RoundTripper {
 			return tripper.NewLoggingRoundTripper(transport)
 		},
 	}
-	return accrual.New(addr, tripperFc)
+	return accrual.New(addr, timeout, limiter, tripperFc)
 }
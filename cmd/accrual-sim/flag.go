@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"github.com/DimKa163/gophermart/app/accrualsim"
+	"os"
+)
+
+func ParseFlags(config *accrualsim.Config) {
+	flag.StringVar(&config.Addr, "a", ":8081", "The address to listen on")
+	flag.StringVar(&config.Scenario, "f", "", "scenario file, without it every order is answered with 204")
+	flag.StringVar(&config.LogLevel, "l", "info", "Log level")
+	flag.Parse()
+
+	if addrValue := os.Getenv("RUN_ADDRESS"); addrValue != "" {
+		config.Addr = addrValue
+	}
+	if scenarioValue := os.Getenv("ACCRUAL_SCENARIO"); scenarioValue != "" {
+		config.Scenario = scenarioValue
+	}
+	if logLevelValue := os.Getenv("LOG_LEVEL"); logLevelValue != "" {
+		config.LogLevel = logLevelValue
+	}
+}
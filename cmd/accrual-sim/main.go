@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/app/accrualsim"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+func main() {
+	var conf accrualsim.Config
+	ParseFlags(&conf)
+	server, err := accrualsim.NewServer(conf)
+	if err != nil {
+		fmt.Printf("Error loading scenario: %v", err)
+		return
+	}
+
+	if err = server.AddLogging(); err != nil {
+		fmt.Printf("Error adding logging: %v", err)
+		return
+	}
+
+	if err = server.Run(); err != nil {
+		if !errors.Is(err, http.ErrServerClosed) {
+			logging.Log.Fatal("Failed to run server", zap.Error(err))
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/DimKa163/gophermart/internal/env"
 	"github.com/DimKa163/gophermart/internal/shared/auth"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 func ParseFlags(config *gophermart.Config) {
@@ -21,6 +23,49 @@ func ParseFlags(config *gophermart.Config) {
 	flag.StringVar(&config.Secret, "s", "secret", "Secret service")
 	flag.StringVar(&config.Secret, "l", "info", "Log level")
 	flag.StringVar(&config.CronSchedule, "sch", "*/10 * * * * *", "schedule")
+	flag.StringVar(&config.AdminKey, "ak", "", "admin api key")
+	flag.StringVar(&config.PartnerKey, "pk", "", "partner api key")
+	flag.UintVar(&config.Expiration.Months, "em", 0, "points expiration in months, 0 disables expiration")
+	flag.DurationVar(&config.Expiration.Notice, "en", 30*24*time.Hour, "points expiration notice period")
+	flag.StringVar(&config.Expiration.Schedule, "esch", "0 0 3 * * *", "points expiration schedule")
+	flag.BoolVar(&config.Expiration.DryRun, "edr", false, "points expiration dry run")
+	flag.Float64Var(&config.Transfer.DailyAmount, "tda", 0, "daily transfer amount limit, 0 disables the limit")
+	flag.UintVar(&config.Transfer.DailyCount, "tdc", 0, "daily transfer count limit, 0 disables the limit")
+	flag.Float64Var(&config.Withdrawal.MinAmount, "wmin", 0, "minimum withdrawal amount, 0 disables the rule")
+	flag.Float64Var(&config.Withdrawal.MaxAmount, "wmax", 0, "maximum withdrawal amount per transaction, 0 disables the rule")
+	flag.Float64Var(&config.Withdrawal.DailyCap, "wdc", 0, "daily withdrawal cap, 0 disables the rule")
+	flag.Float64Var(&config.Withdrawal.MonthlyCap, "wmc", 0, "monthly withdrawal cap, 0 disables the rule")
+	flag.Float64Var(&config.Withdrawal.MaxOrderShare, "wos", 0, "maximum share of an order payable with points, 0 disables the rule")
+	flag.DurationVar(&config.Withdrawal.CoolingOff, "wco", 0, "cooling-off period after the first accrual, 0 disables the rule")
+	flag.StringVar(&config.Providers.List, "apl", "", "additional accrual providers as name=address[;timeout=30s][;rate=5] separated by commas")
+	flag.StringVar(&config.Providers.Routes, "apr", "", "accrual provider routes as number prefix=provider separated by commas")
+	flag.DurationVar(&config.Providers.Timeout, "at", 30*time.Second, "accrual request timeout")
+	flag.StringVar(&config.Callback.Secret, "acs", "", "accrual callback signing secret, empty disables callbacks")
+	flag.DurationVar(&config.Callback.Tolerance, "act", 5*time.Minute, "accepted age of accrual callback signatures")
+	flag.UintVar(&config.Tracking.BatchSize, "tbs", 100, "number of orders tracked per batch")
+	flag.UintVar(&config.Tracking.Concurrency, "tcc", 10, "number of concurrent accrual requests")
+	flag.Float64Var(&config.Tracking.RateLimit, "trl", 0, "accrual requests per second, 0 disables the limit")
+	flag.DurationVar(&config.Tracking.RegisteredInterval, "tri", 10*time.Second, "check interval for orders registered by the accrual service")
+	flag.DurationVar(&config.Tracking.ProcessingInterval, "tpi", time.Minute, "check interval for orders processed by the accrual service")
+	flag.DurationVar(&config.Tracking.ErrorBackoff, "tbe", 30*time.Second, "first delay after a failed order check, doubled on every failure")
+	flag.DurationVar(&config.Tracking.MaxBackoff, "tbm", time.Hour, "maximum delay after failed order checks")
+	flag.DurationVar(&config.Tracking.LeaseTTL, "tlt", 5*time.Minute, "how long claimed orders stay reserved for this instance")
+	flag.UintVar(&config.Tracking.MaxAttempts, "tma", 10, "failed checks in a row before an order is dead-lettered, 0 retries forever")
+	flag.Float64Var(&config.Tracking.MaxAccrual, "tmac", 0, "largest accrual accepted for an order, larger results are quarantined, 0 disables the check")
+	flag.DurationVar(&config.Tracking.ElectionInterval, "tei", 5*time.Second, "leader election interval of the order tracking worker")
+	flag.UintVar(&config.Tracking.History, "tch", 20, "number of latest tracking cycles kept for the status")
+	flag.DurationVar(&config.Tracking.NotifyDebounce, "tnd", time.Second, "delay coalescing upload notifications into one tracking cycle")
+	flag.UintVar(&config.Breaker.ConsecutiveFailures, "cbf", 5, "consecutive accrual failures that open the circuit breaker, 0 disables the rule")
+	flag.Float64Var(&config.Breaker.ErrorRate, "cber", 0.5, "accrual error rate that opens the circuit breaker, 0 disables the rule")
+	flag.UintVar(&config.Breaker.MinRequests, "cbmr", 10, "requests in the window before the error rate is considered")
+	flag.DurationVar(&config.Breaker.Window, "cbw", time.Minute, "circuit breaker error rate window")
+	flag.DurationVar(&config.Breaker.OpenTimeout, "cbot", 30*time.Second, "how long the circuit breaker stays open before probing")
+	flag.UintVar(&config.Breaker.HalfOpenProbes, "cbp", 1, "successful probes that close the circuit breaker")
+	flag.StringVar(&config.SnapshotSchedule, "bss", "0 30 1 * * *", "balance snapshot schedule")
+	flag.DurationVar(&config.ShutdownTimeout, "st", 15*time.Second, "graceful shutdown timeout")
+	flag.StringVar(&config.Export.Dir, "exd", filepath.Join(os.TempDir(), "gophermart-exports"), "export files directory")
+	flag.DurationVar(&config.Export.TTL, "ext", 24*time.Hour, "export download link ttl")
+	flag.StringVar(&config.Export.Schedule, "exsch", "*/5 * * * * *", "export runner schedule")
 	flag.UintVar(&argonMemory, "m", 64, "argon memory")
 	flag.UintVar(&argonIterations, "i", 3, "argon iteration")
 	flag.UintVar(&argonParallelism, "pr", 2, "argon parallelism")
@@ -54,6 +99,67 @@ func ParseFlags(config *gophermart.Config) {
 	if envScheduleLog := os.Getenv("WORKER_SCHEDULE"); envScheduleLog != "" {
 		config.CronSchedule = envScheduleLog
 	}
+	if adminKeyValue := os.Getenv("ADMIN_API_KEY"); adminKeyValue != "" {
+		config.AdminKey = adminKeyValue
+	}
+	if partnerKeyValue := os.Getenv("PARTNER_API_KEY"); partnerKeyValue != "" {
+		config.PartnerKey = partnerKeyValue
+	}
+	if providersValue := os.Getenv("ACCRUAL_PROVIDERS"); providersValue != "" {
+		config.Providers.List = providersValue
+	}
+	if routesValue := os.Getenv("ACCRUAL_ROUTES"); routesValue != "" {
+		config.Providers.Routes = routesValue
+	}
+	if callbackSecretValue := os.Getenv("ACCRUAL_CALLBACK_SECRET"); callbackSecretValue != "" {
+		config.Callback.Secret = callbackSecretValue
+	}
+	if envExpirationSchedule := os.Getenv("EXPIRATION_SCHEDULE"); envExpirationSchedule != "" {
+		config.Expiration.Schedule = envExpirationSchedule
+	}
+	if envSnapshotSchedule := os.Getenv("BALANCE_SNAPSHOT_SCHEDULE"); envSnapshotSchedule != "" {
+		config.SnapshotSchedule = envSnapshotSchedule
+	}
+	if exportDirValue := os.Getenv("EXPORT_DIR"); exportDirValue != "" {
+		config.Export.Dir = exportDirValue
+	}
+	if envExportSchedule := os.Getenv("EXPORT_SCHEDULE"); envExportSchedule != "" {
+		config.Export.Schedule = envExportSchedule
+	}
+	env.ParseUIntEnv("POINTS_EXPIRATION_MONTHS", &config.Expiration.Months)
+	env.ParseDurationEnv("POINTS_EXPIRATION_NOTICE", &config.Expiration.Notice)
+	env.ParseBoolEnv("EXPIRATION_DRY_RUN", &config.Expiration.DryRun)
+	env.ParseFloatEnv("TRANSFER_DAILY_AMOUNT", &config.Transfer.DailyAmount)
+	env.ParseUIntEnv("TRANSFER_DAILY_COUNT", &config.Transfer.DailyCount)
+	env.ParseDurationEnv("EXPORT_TTL", &config.Export.TTL)
+	env.ParseFloatEnv("WITHDRAWAL_MIN_AMOUNT", &config.Withdrawal.MinAmount)
+	env.ParseFloatEnv("WITHDRAWAL_MAX_AMOUNT", &config.Withdrawal.MaxAmount)
+	env.ParseFloatEnv("WITHDRAWAL_DAILY_CAP", &config.Withdrawal.DailyCap)
+	env.ParseFloatEnv("WITHDRAWAL_MONTHLY_CAP", &config.Withdrawal.MonthlyCap)
+	env.ParseFloatEnv("WITHDRAWAL_MAX_ORDER_SHARE", &config.Withdrawal.MaxOrderShare)
+	env.ParseDurationEnv("WITHDRAWAL_COOLING_OFF", &config.Withdrawal.CoolingOff)
+	env.ParseDurationEnv("ACCRUAL_TIMEOUT", &config.Providers.Timeout)
+	env.ParseDurationEnv("ACCRUAL_CALLBACK_TOLERANCE", &config.Callback.Tolerance)
+	env.ParseDurationEnv("SHUTDOWN_TIMEOUT", &config.ShutdownTimeout)
+	env.ParseUIntEnv("TRACKING_BATCH_SIZE", &config.Tracking.BatchSize)
+	env.ParseUIntEnv("TRACKING_CONCURRENCY", &config.Tracking.Concurrency)
+	env.ParseFloatEnv("ACCRUAL_RATE_LIMIT", &config.Tracking.RateLimit)
+	env.ParseDurationEnv("TRACKING_REGISTERED_INTERVAL", &config.Tracking.RegisteredInterval)
+	env.ParseDurationEnv("TRACKING_PROCESSING_INTERVAL", &config.Tracking.ProcessingInterval)
+	env.ParseDurationEnv("TRACKING_ERROR_BACKOFF", &config.Tracking.ErrorBackoff)
+	env.ParseDurationEnv("TRACKING_MAX_BACKOFF", &config.Tracking.MaxBackoff)
+	env.ParseDurationEnv("TRACKING_LEASE_TTL", &config.Tracking.LeaseTTL)
+	env.ParseUIntEnv("TRACKING_MAX_ATTEMPTS", &config.Tracking.MaxAttempts)
+	env.ParseFloatEnv("TRACKING_MAX_ACCRUAL", &config.Tracking.MaxAccrual)
+	env.ParseDurationEnv("TRACKING_ELECTION_INTERVAL", &config.Tracking.ElectionInterval)
+	env.ParseUIntEnv("TRACKING_CYCLE_HISTORY", &config.Tracking.History)
+	env.ParseDurationEnv("TRACKING_NOTIFY_DEBOUNCE", &config.Tracking.NotifyDebounce)
+	env.ParseUIntEnv("CIRCUIT_BREAKER_FAILURES", &config.Breaker.ConsecutiveFailures)
+	env.ParseFloatEnv("CIRCUIT_BREAKER_ERROR_RATE", &config.Breaker.ErrorRate)
+	env.ParseUIntEnv("CIRCUIT_BREAKER_MIN_REQUESTS", &config.Breaker.MinRequests)
+	env.ParseDurationEnv("CIRCUIT_BREAKER_WINDOW", &config.Breaker.Window)
+	env.ParseDurationEnv("CIRCUIT_BREAKER_OPEN_TIMEOUT", &config.Breaker.OpenTimeout)
+	env.ParseUIntEnv("CIRCUIT_BREAKER_PROBES", &config.Breaker.HalfOpenProbes)
 	env.ParseUIntEnv("ARGON_MEMORY", &argonMemory)
 	env.ParseUIntEnv("ARGON_ITERATION", &argonIterations)
 	env.ParseUIntEnv("ARGON_PARALLELISM", &argonParallelism)
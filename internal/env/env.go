@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 func ParseUIntEnv(name string, defValue *uint) {
@@ -12,3 +13,27 @@ func ParseUIntEnv(name string, defValue *uint) {
 		}
 	}
 }
+
+func ParseFloatEnv(name string, defValue *float64) {
+	if envValue := os.Getenv(name); envValue != "" {
+		if value, err := strconv.ParseFloat(envValue, 64); err == nil {
+			*defValue = value
+		}
+	}
+}
+
+func ParseBoolEnv(name string, defValue *bool) {
+	if envValue := os.Getenv(name); envValue != "" {
+		if value, err := strconv.ParseBool(envValue); err == nil {
+			*defValue = value
+		}
+	}
+}
+
+func ParseDurationEnv(name string, defValue *time.Duration) {
+	if envValue := os.Getenv(name); envValue != "" {
+		if value, err := time.ParseDuration(envValue); err == nil {
+			*defValue = value
+		}
+	}
+}
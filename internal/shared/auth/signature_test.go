@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"order":"12345678903","status":"PROCESSED","accrual":500}`)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := Sign(secret, timestamp, body)
+	cases := []struct {
+		name        string
+		timestamp   string
+		body        []byte
+		signature   string
+		now         time.Time
+		expectedErr error
+	}{
+		{
+			name:      "valid",
+			timestamp: timestamp,
+			body:      body,
+			signature: signature,
+			now:       now.Add(time.Minute),
+		},
+		{
+			name:        "tampered body",
+			timestamp:   timestamp,
+			body:        []byte(`{"order":"12345678903","status":"PROCESSED","accrual":5000}`),
+			signature:   signature,
+			now:         now,
+			expectedErr: ErrSignatureInvalid,
+		},
+		{
+			name:        "other timestamp",
+			timestamp:   strconv.FormatInt(now.Unix()+1, 10),
+			body:        body,
+			signature:   signature,
+			now:         now,
+			expectedErr: ErrSignatureInvalid,
+		},
+		{
+			name:        "replayed",
+			timestamp:   timestamp,
+			body:        body,
+			signature:   signature,
+			now:         now.Add(10 * time.Minute),
+			expectedErr: ErrSignatureExpired,
+		},
+		{
+			name:        "malformed timestamp",
+			timestamp:   "yesterday",
+			body:        body,
+			signature:   signature,
+			now:         now,
+			expectedErr: ErrSignatureInvalid,
+		},
+		{
+			name:        "malformed signature",
+			timestamp:   timestamp,
+			body:        body,
+			signature:   "not-hex",
+			now:         now,
+			expectedErr: ErrSignatureInvalid,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := VerifySignature(secret, c.timestamp, c.body, c.signature, c.now, 5*time.Minute)
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
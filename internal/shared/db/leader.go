@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	tryAdvisoryLockSQL = `SELECT pg_try_advisory_lock($1)`
+	advisoryUnlockSQL  = `SELECT pg_advisory_unlock($1)`
+	heartbeatSQL       = `SELECT 1`
+)
+
+type Role int
+
+const (
+	RoleFollower Role = iota
+	RoleLeader
+)
+
+func (r Role) String() string {
+	return [...]string{"FOLLOWER", "LEADER"}[r]
+}
+
+type LeaderStatus struct {
+	Instance string
+	Role     Role
+	Since    time.Time
+}
+
+// LeaderElector holds a session level advisory lock on a dedicated connection, the instance owning it is the leader.
+// When the leader dies its session ends, the lock is freed and a follower takes it on its next attempt.
+type LeaderElector struct {
+	pool     *pgxpool.Pool
+	key      int64
+	instance string
+	interval time.Duration
+	mu       sync.RWMutex
+	conn     *pgxpool.Conn
+	role     Role
+	since    time.Time
+}
+
+func NewLeaderElector(pool *pgxpool.Pool, key int64, instance string, interval time.Duration) *LeaderElector {
+	return &LeaderElector{
+		pool:     pool,
+		key:      key,
+		instance: instance,
+		interval: interval,
+		since:    time.Now(),
+	}
+}
+
+func (e *LeaderElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.role == RoleLeader
+}
+
+func (e *LeaderElector) Status() LeaderStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return LeaderStatus{Instance: e.instance, Role: e.role, Since: e.since}
+}
+
+// Run campaigns for leadership and checks the held lock every interval until ctx is done.
+func (e *LeaderElector) Run(ctx context.Context) {
+	logger := logging.Logger(ctx).With(zap.String("instance", e.instance), zap.Int64("lock", e.key))
+	ctx = logging.SetLogger(ctx, logger)
+	logger.Info("joined leader election", zap.String("role", RoleFollower.String()))
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		e.tick(ctx)
+		select {
+		case <-ctx.Done():
+			e.resign(context.Background())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *LeaderElector) tick(ctx context.Context) {
+	if e.conn != nil {
+		if _, err := e.conn.Exec(ctx, heartbeatSQL); err != nil {
+			logging.Logger(ctx).Warn("lost the leader connection", zap.Error(err))
+			// the session may be gone for good, closing the connection frees the lock if it is not
+			_ = e.conn.Conn().Close(context.Background())
+			e.conn.Release()
+			e.conn = nil
+			e.setRole(ctx, RoleFollower)
+		}
+		return
+	}
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		logging.Logger(ctx).Warn("failed to acquire a connection for leader election", zap.Error(err))
+		return
+	}
+	var acquired bool
+	if err = conn.QueryRow(ctx, tryAdvisoryLockSQL, e.key).Scan(&acquired); err != nil || !acquired {
+		if err != nil {
+			logging.Logger(ctx).Warn("failed to take the leader lock", zap.Error(err))
+		}
+		conn.Release()
+		return
+	}
+	e.conn = conn
+	e.setRole(ctx, RoleLeader)
+}
+
+func (e *LeaderElector) resign(ctx context.Context) {
+	if e.conn == nil {
+		return
+	}
+	_, _ = e.conn.Exec(ctx, advisoryUnlockSQL, e.key)
+	e.conn.Release()
+	e.conn = nil
+	e.setRole(ctx, RoleFollower)
+}
+
+func (e *LeaderElector) setRole(ctx context.Context, role Role) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.role == role {
+		return
+	}
+	logging.Logger(ctx).Info("leader election role changed",
+		zap.String("from", e.role.String()),
+		zap.String("to", role.String()))
+	e.role = role
+	e.since = time.Now()
+}
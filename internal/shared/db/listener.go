@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Listener receives Postgres notifications of a channel on a dedicated connection and reconnects when it is lost.
+type Listener struct {
+	pool    *pgxpool.Pool
+	channel string
+	retry   time.Duration
+}
+
+func NewListener(pool *pgxpool.Pool, channel string) *Listener {
+	return &Listener{pool: pool, channel: channel, retry: 5 * time.Second}
+}
+
+// Listen calls fn with the payload of every notification until ctx is done.
+func (l *Listener) Listen(ctx context.Context, fn func(payload string)) {
+	logger := logging.Logger(ctx).With(zap.String("channel", l.channel))
+	for ctx.Err() == nil {
+		err := l.listen(ctx, fn)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("notification listener stopped, reconnecting", zap.Error(err), zap.Duration("after", l.retry))
+		select {
+		case <-ctx.Done():
+		case <-time.After(l.retry):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context, fn func(payload string)) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// a listening session must not go back to the pool
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return err
+	}
+	logging.Logger(ctx).Info("listening for notifications", zap.String("channel", l.channel))
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		fn(notification.Payload)
+	}
+}
@@ -0,0 +1,228 @@
+package tripper
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without making a request while the breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+var breakerStateNames = [...]string{"CLOSED", "OPEN", "HALF_OPEN"}
+
+func (s BreakerState) String() string {
+	return breakerStateNames[s]
+}
+
+type BreakerConfig struct {
+	// ConsecutiveFailures opens the breaker after that many failures in a row, 0 disables the rule.
+	ConsecutiveFailures int
+	// ErrorRate opens the breaker when the share of failures in the window reaches it, 0 disables the rule.
+	ErrorRate float64
+	// MinRequests is how many requests the window needs before the error rate is considered.
+	MinRequests int
+	Window      time.Duration
+	// OpenTimeout is how long the breaker stays open before letting probes through.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is how many successful probes close the breaker again.
+	HalfOpenProbes int
+}
+
+type BreakerStatus struct {
+	Name                string
+	State               BreakerState
+	Requests            int
+	Failures            int
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+	RetryAt             time.Time
+}
+
+type CircuitBreaker struct {
+	name   string
+	config BreakerConfig
+	mu     sync.Mutex
+	state  BreakerState
+	// window counters, reset when the window elapses or the state changes
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+func NewCircuitBreaker(name string, config BreakerConfig) *CircuitBreaker {
+	if config.HalfOpenProbes < 1 {
+		config.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{name: name, config: config, windowStart: time.Now()}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current(time.Now())
+}
+
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := BreakerStatus{
+		Name:                b.name,
+		State:               b.current(time.Now()),
+		Requests:            b.requests,
+		Failures:            b.failures,
+		ConsecutiveFailures: b.consecutive,
+		OpenedAt:            b.openedAt,
+	}
+	if status.State == BreakerOpen {
+		status.RetryAt = b.openedAt.Add(b.config.OpenTimeout)
+	}
+	return status
+}
+
+// Allow reserves a call, in the half-open state only the configured number of probes get through.
+func (b *CircuitBreaker) Allow(r *http.Request) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current(time.Now()) {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.state != BreakerHalfOpen {
+			b.transition(r, BreakerHalfOpen)
+		}
+		if b.probes >= b.config.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) Record(r *http.Request, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.state == BreakerHalfOpen {
+		b.probes--
+		if !success {
+			b.open(r, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenProbes {
+			b.transition(r, BreakerClosed)
+		}
+		return
+	}
+	if b.state != BreakerClosed {
+		return
+	}
+	if b.config.Window > 0 && now.Sub(b.windowStart) > b.config.Window {
+		b.reset(now)
+	}
+	b.requests++
+	if success {
+		b.consecutive = 0
+		return
+	}
+	b.failures++
+	b.consecutive++
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		b.open(r, now)
+		return
+	}
+	if b.config.ErrorRate > 0 && b.requests >= b.config.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.config.ErrorRate {
+		b.open(r, now)
+	}
+}
+
+// release gives back a reserved call that says nothing about the health of the remote side.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// current resolves an expired open state to half-open without changing the stored state.
+func (b *CircuitBreaker) current(now time.Time) BreakerState {
+	if b.state == BreakerOpen && !now.Before(b.openedAt.Add(b.config.OpenTimeout)) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (b *CircuitBreaker) open(r *http.Request, now time.Time) {
+	b.openedAt = now
+	b.transition(r, BreakerOpen)
+}
+
+func (b *CircuitBreaker) transition(r *http.Request, state BreakerState) {
+	logger := logging.Logger(r.Context()).With(zap.String("breaker", b.name),
+		zap.String("from", b.state.String()),
+		zap.String("to", state.String()))
+	switch state {
+	case BreakerOpen:
+		logger.Warn("circuit breaker opened",
+			zap.Int("requests", b.requests),
+			zap.Int("failures", b.failures),
+			zap.Int("consecutive_failures", b.consecutive),
+			zap.Duration("open_timeout", b.config.OpenTimeout))
+	default:
+		logger.Info("circuit breaker state changed")
+	}
+	b.state = state
+	b.probes = 0
+	b.successes = 0
+	if state == BreakerClosed {
+		b.reset(time.Now())
+	}
+}
+
+func (b *CircuitBreaker) reset(now time.Time) {
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.consecutive = 0
+}
+
+type circuitBreakerRoundTripper struct {
+	rt      http.RoundTripper
+	breaker *CircuitBreaker
+}
+
+func NewCircuitBreakerRoundTripper(rt http.RoundTripper, breaker *CircuitBreaker) http.RoundTripper {
+	return &circuitBreakerRoundTripper{rt: rt, breaker: breaker}
+}
+
+func (rt *circuitBreakerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := rt.breaker.Allow(req); err != nil {
+		return nil, err
+	}
+	resp, err := rt.rt.RoundTrip(req)
+	if errors.Is(err, ErrPaused) || errors.Is(err, context.Canceled) {
+		rt.breaker.release()
+		return resp, err
+	}
+	rt.breaker.Record(req, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
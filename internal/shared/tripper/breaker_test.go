@@ -0,0 +1,79 @@
+package tripper
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type stubRoundTripper struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (s *stubRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &http.Response{StatusCode: s.status, Request: r}, nil
+}
+
+func TestCircuitBreakerShouldOpenOnConsecutiveFailures(t *testing.T) {
+	stub := &stubRoundTripper{err: errors.New("connection refused")}
+	breaker := NewCircuitBreaker("test", BreakerConfig{ConsecutiveFailures: 3, OpenTimeout: time.Hour})
+	rt := NewCircuitBreakerRoundTripper(stub, breaker)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for i := 0; i < 3; i++ {
+		_, _ = rt.RoundTrip(req)
+	}
+	_, err := rt.RoundTrip(req)
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, stub.calls)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.False(t, breaker.Status().RetryAt.IsZero())
+}
+
+func TestCircuitBreakerShouldOpenOnErrorRate(t *testing.T) {
+	stub := &stubRoundTripper{}
+	breaker := NewCircuitBreaker("test", BreakerConfig{ErrorRate: 0.5, MinRequests: 4, Window: time.Minute, OpenTimeout: time.Hour})
+	rt := NewCircuitBreakerRoundTripper(stub, breaker)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, status := range []int{http.StatusOK, http.StatusBadGateway, http.StatusOK} {
+		stub.status = status
+		_, _ = rt.RoundTrip(req)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	stub.status = http.StatusServiceUnavailable
+	_, _ = rt.RoundTrip(req)
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func TestCircuitBreakerShouldCloseAfterSuccessfulProbe(t *testing.T) {
+	stub := &stubRoundTripper{status: http.StatusInternalServerError}
+	breaker := NewCircuitBreaker("test", BreakerConfig{ConsecutiveFailures: 1, OpenTimeout: 10 * time.Millisecond})
+	rt := NewCircuitBreakerRoundTripper(stub, breaker)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, _ = rt.RoundTrip(req)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+
+	_, _ = rt.RoundTrip(req)
+	assert.Equal(t, BreakerOpen, breaker.State(), "a failed probe opens the breaker again")
+	time.Sleep(20 * time.Millisecond)
+
+	stub.status = http.StatusOK
+	_, err := rt.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
@@ -0,0 +1,97 @@
+package tripper
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrPaused is returned instead of making a request while the remote side asked us to back off.
+var ErrPaused = errors.New("requests are paused by the remote rate limit")
+
+// RateLimiter spaces calls evenly so no more than the configured number of requests per second are made.
+// It is shared by every request of a client, so a pause stops all of its traffic.
+type RateLimiter struct {
+	mu          sync.Mutex
+	interval    time.Duration
+	next        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a limiter that only honours pauses when rps is not positive.
+func NewRateLimiter(rps float64) *RateLimiter {
+	l := &RateLimiter{}
+	l.SetRate(rps)
+	return l
+}
+
+// SetRate changes the rate for the following calls, a non-positive rps disables spacing.
+func (l *RateLimiter) SetRate(rps float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rps <= 0 {
+		l.interval = 0
+		return
+	}
+	l.interval = time.Duration(float64(time.Second) / rps)
+}
+
+// Pause rejects calls with ErrPaused until the given time.
+func (l *RateLimiter) Pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+func (l *RateLimiter) PausedUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pausedUntil
+}
+
+// Wait blocks until the caller's slot comes up or the request context is done.
+func (l *RateLimiter) Wait(r *http.Request) error {
+	l.mu.Lock()
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		l.mu.Unlock()
+		return ErrPaused
+	}
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return r.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type rateLimitRoundTripper struct {
+	rt      http.RoundTripper
+	limiter *RateLimiter
+}
+
+func NewRateLimitRoundTripper(rt http.RoundTripper, limiter *RateLimiter) http.RoundTripper {
+	return &rateLimitRoundTripper{rt: rt, limiter: limiter}
+}
+
+func (rt *rateLimitRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := rt.limiter.Wait(req); err != nil {
+		return nil, err
+	}
+	return rt.rt.RoundTrip(req)
+}
@@ -58,25 +58,20 @@ func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 				}
 			}
 			attempt++
-			return nil, backoff.RetryAfter(times[seconds])
+			return nil, backoff.RetryAfter(seconds)
 		}
 		return response, nil
 	}, backoff.WithBackOff(backoff.NewExponentialBackOff()))
 }
 
+// shouldRetry leaves 429 to the caller, a rate limit applies to all requests and not just the one being retried.
 func (rt *retryRoundTripper) shouldRetry(resp *http.Response) bool {
 	switch resp.StatusCode {
-	case http.StatusRequestTimeout:
-	case http.StatusTooManyRequests:
-	case http.StatusBadGateway:
-	case http.StatusGatewayTimeout:
-	case http.StatusServiceUnavailable:
 	case http.StatusInternalServerError:
 		return true
 	default:
 		return false
 	}
-	return false
 }
 
 func (rt *retryRoundTripper) drain(response *http.Response) error {
@@ -0,0 +1,64 @@
+package tripper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type retryAfterRoundTripper struct {
+	stubRoundTripper
+	retryAfter string
+}
+
+func (s *retryAfterRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := s.stubRoundTripper.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	resp.Header = http.Header{"Retry-After": []string{s.retryAfter}}
+	return resp, nil
+}
+
+func TestRetryRoundTripper(t *testing.T) {
+	cases := []struct {
+		name          string
+		status        int
+		expectedCalls int
+	}{
+		{
+			name:          "internal server error is retried",
+			status:        http.StatusInternalServerError,
+			expectedCalls: 4,
+		},
+		{
+			name:          "too many requests is left to the caller",
+			status:        http.StatusTooManyRequests,
+			expectedCalls: 1,
+		},
+		{
+			name:          "service unavailable is not retried",
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 1,
+		},
+		{
+			name:          "ok is returned as is",
+			status:        http.StatusOK,
+			expectedCalls: 1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Retry-After is honoured, zero keeps the test fast
+			stub := &retryAfterRoundTripper{stubRoundTripper: stubRoundTripper{status: c.status}, retryAfter: "0"}
+			rt := NewRetryRoundTripper(stub)
+
+			resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.status, resp.StatusCode)
+			assert.Equal(t, c.expectedCalls, stub.calls)
+		})
+	}
+}
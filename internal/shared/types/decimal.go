@@ -3,6 +3,7 @@ package types
 import (
 	"database/sql/driver"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
@@ -10,8 +11,7 @@ type Decimal struct {
 	decimal.Decimal
 }
 
-func (d *Decimal) MarshalJSON() ([]byte, error) {
-
+func (d Decimal) MarshalJSON() ([]byte, error) {
 	a := []byte(d.String())
 	return a, nil
 }
@@ -59,3 +59,16 @@ func (d Decimal) IsZero() bool {
 func (d *Decimal) Value() (driver.Value, error) {
 	return d.Decimal.String(), nil
 }
+
+// ScanNumeric lets pgx scan numeric columns without going through strings.
+func (d *Decimal) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("Decimal.ScanNumeric: unsupported value")
+	}
+	d.Decimal = decimal.NewFromBigInt(v.Int, v.Exp)
+	return nil
+}
+
+func (d Decimal) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: d.Coefficient(), Exp: d.Exponent(), Valid: true}, nil
+}
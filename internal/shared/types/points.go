@@ -0,0 +1,138 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// PointsScale is the number of decimal places points are stored with, matching DECIMAL(10, 2) columns.
+const PointsScale = 2
+
+// MaxPoints is the largest amount a DECIMAL(10, 2) column holds.
+var MaxPoints = Points{value: decimal.New(9999999999, -PointsScale)}
+
+var (
+	ErrPointsInvalid  = errors.New("points must be a number")
+	ErrPointsNegative = errors.New("points must not be negative")
+	ErrPointsScale    = errors.New("points must have at most 2 decimal places")
+	ErrPointsRange    = errors.New("points exceed the maximum amount")
+)
+
+// Points is a non-negative amount of bonus points with a fixed scale of two decimal places.
+// The zero value is zero points.
+type Points struct {
+	value decimal.Decimal
+}
+
+// NewPoints validates the amount strictly: it is never rounded.
+func NewPoints(d decimal.Decimal) (Points, error) {
+	if d.IsNegative() {
+		return Points{}, ErrPointsNegative
+	}
+	if !d.Equal(d.Truncate(PointsScale)) {
+		return Points{}, ErrPointsScale
+	}
+	if d.GreaterThan(MaxPoints.value) {
+		return Points{}, ErrPointsRange
+	}
+	return Points{value: d.Truncate(PointsScale)}, nil
+}
+
+// RoundPoints rounds the amount half away from zero to the points scale, used for computed amounts.
+func RoundPoints(d decimal.Decimal) (Points, error) {
+	return NewPoints(d.Round(PointsScale))
+}
+
+func ParsePoints(value string) (Points, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Points{}, ErrPointsInvalid
+	}
+	return NewPoints(d)
+}
+
+// PointsFromInt is meant for constants and tests, it panics on negative or too large values.
+func PointsFromInt(value int64) Points {
+	p, err := NewPoints(decimal.NewFromInt(value))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// IsInvalidPoints reports whether err comes from rejecting an amount of points.
+func IsInvalidPoints(err error) bool {
+	return errors.Is(err, ErrPointsInvalid) ||
+		errors.Is(err, ErrPointsNegative) ||
+		errors.Is(err, ErrPointsScale) ||
+		errors.Is(err, ErrPointsRange)
+}
+
+func (p Points) Decimal() Decimal {
+	return Decimal{p.value}
+}
+
+func (p Points) Add(a Points) Points {
+	return Points{value: p.value.Add(a.value)}
+}
+
+// Sub subtracts a, the result is floored at zero since points can not go negative.
+func (p Points) Sub(a Points) Points {
+	if a.value.GreaterThan(p.value) {
+		return Points{}
+	}
+	return Points{value: p.value.Sub(a.value)}
+}
+
+func (p Points) Cmp(a Points) int {
+	return p.value.Cmp(a.value)
+}
+
+func (p Points) IsZero() bool {
+	return p.value.IsZero()
+}
+
+func (p Points) IsPositive() bool {
+	return p.value.IsPositive()
+}
+
+func (p Points) String() string {
+	return p.value.String()
+}
+
+func (p Points) MarshalJSON() ([]byte, error) {
+	return []byte(p.value.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string and rejects amounts NewPoints rejects.
+func (p *Points) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return ErrPointsInvalid
+	}
+	points, err := ParsePoints(string(data))
+	if err != nil {
+		return err
+	}
+	*p = points
+	return nil
+}
+
+// ScanNumeric lets pgx scan numeric columns into points without going through strings.
+func (p *Points) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+		return ErrPointsInvalid
+	}
+	points, err := RoundPoints(decimal.NewFromBigInt(v.Int, v.Exp))
+	if err != nil {
+		return err
+	}
+	*p = points
+	return nil
+}
+
+func (p Points) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: p.value.Coefficient(), Exp: p.value.Exponent(), Valid: true}, nil
+}
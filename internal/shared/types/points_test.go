@@ -0,0 +1,100 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestParsePoints(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected string
+		err      error
+	}{
+		{name: "integer", value: "500", expected: "500"},
+		{name: "two places", value: "10.25", expected: "10.25"},
+		{name: "trailing zeros", value: "10.500", expected: "10.5"},
+		{name: "zero", value: "0", expected: "0"},
+		{name: "maximum", value: "99999999.99", expected: "99999999.99"},
+		{name: "negative", value: "-1", err: ErrPointsNegative},
+		{name: "three places", value: "1.005", err: ErrPointsScale},
+		{name: "too large", value: "100000000", err: ErrPointsRange},
+		{name: "not a number", value: "ten", err: ErrPointsInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := ParsePoints(c.value)
+			if c.err != nil {
+				assert.ErrorIs(t, err, c.err)
+				assert.True(t, IsInvalidPoints(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, p.String())
+		})
+	}
+}
+
+func TestRoundPointsShouldRoundHalfAwayFromZero(t *testing.T) {
+	p, err := RoundPoints(decimal.RequireFromString("1.005"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.01", p.String())
+
+	p, err = RoundPoints(decimal.RequireFromString("1.004"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", p.String())
+
+	_, err = RoundPoints(decimal.RequireFromString("-0.01"))
+	assert.ErrorIs(t, err, ErrPointsNegative)
+}
+
+func TestPointsSubShouldNotGoBelowZero(t *testing.T) {
+	assert.True(t, PointsFromInt(5).Sub(PointsFromInt(10)).IsZero())
+	assert.Equal(t, "5", PointsFromInt(10).Sub(PointsFromInt(5)).String())
+}
+
+func TestPointsJSON(t *testing.T) {
+	var body struct {
+		Sum  Points  `json:"sum"`
+		Opt  *Points `json:"opt"`
+		Text Points  `json:"text"`
+	}
+	err := json.Unmarshal([]byte(`{"sum": 12.5, "text": "3.75"}`), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "12.5", body.Sum.String())
+	assert.Nil(t, body.Opt)
+	assert.Equal(t, "3.75", body.Text.String())
+
+	err = json.Unmarshal([]byte(`{"sum": -12.5}`), &body)
+	assert.ErrorIs(t, err, ErrPointsNegative)
+
+	data, err := json.Marshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sum": 12.5, "opt": null, "text": 3.75}`, string(data))
+}
+
+func TestPointsNumeric(t *testing.T) {
+	var p Points
+	err := p.ScanNumeric(pgtype.Numeric{Int: big.NewInt(1250), Exp: -2, Valid: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "12.5", p.String())
+
+	n, err := p.NumericValue()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, decimal.NewFromBigInt(n.Int, n.Exp).Cmp(decimal.RequireFromString("12.5")))
+
+	assert.ErrorIs(t, p.ScanNumeric(pgtype.Numeric{}), ErrPointsInvalid)
+}
+
+func TestDecimalShouldMarshalByValue(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Sum Decimal `json:"sum"`
+	}{Sum: Decimal{decimal.RequireFromString("-4.2")}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sum": -4.2}`, string(data))
+}
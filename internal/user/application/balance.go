@@ -0,0 +1,32 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"time"
+)
+
+type balanceService struct {
+	uow uow.UnitOfWork
+}
+
+func (b *balanceService) At(ctx context.Context, userID int64, at time.Time) (*model.BonusBalance, error) {
+	exists, err := b.uow.UserRepository().Exists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+	return b.uow.BonusBalanceRepository().GetAt(ctx, userID, at)
+}
+
+func (b *balanceService) Snapshot(ctx context.Context, cutoff time.Time) (int64, error) {
+	return b.uow.BonusBalanceRepository().Snapshot(ctx, cutoff)
+}
+
+func NewBalanceService(uow uow.UnitOfWork) domain.BalanceService {
+	return &balanceService{uow: uow}
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBalanceAtForUnknownUserShouldReturnNotFound(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockURepo.EXPECT().Exists(ctx, int64(42)).Return(false, nil)
+
+	sut := NewBalanceService(mockUow)
+
+	_, err := sut.At(ctx, 42, time.Now())
+
+	assert.ErrorIs(t, err, ErrUserNotFound, "At should return not found")
+}
@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+type accrualCallbackService struct {
+	uow    uow.UnitOfWork
+	tiers  *TierEvaluator
+	policy model.TrackingPolicy
+}
+
+// Apply only ever moves an order forward, so repeated callbacks and results that were already polled change nothing.
+// The order row stays locked until the result is saved and a check of the same order running elsewhere loses its lease.
+// Results failing validation are quarantined and answered with ErrAccrualQuarantined.
+func (a *accrualCallbackService) Apply(ctx context.Context, id model.OrderID, status string, accrual *types.Decimal) (*model.Order, error) {
+	var result *model.Order
+	var quarantined *model.QuarantinedResult
+	err := a.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		quarantined = nil
+		order, err := uow.OrderRepository().GetForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+		result = order
+		next, rejected := checkAccrual(order, &dto.Order{Number: id.String(), Status: dto.OrderStatus(status), Accrual: accrual},
+			a.policy.MaxAccrual)
+		if rejected != nil {
+			quarantined = rejected
+			_, err = uow.QuarantineRepository().Insert(ctx, rejected)
+			return err
+		}
+		if order.Status.Final() || next <= order.Status {
+			logging.Logger(ctx).Info("accrual callback ignored, the order is up to date",
+				zap.String("order", id.String()), zap.String("status", order.Status.String()))
+			return nil
+		}
+		if err = applyAccrual(order, next, accrual); err != nil {
+			return err
+		}
+		order.Error = ""
+		order.Schedule(a.policy, time.Now())
+		if err = a.tiers.ApplyBonus(ctx, uow, order); err != nil {
+			return err
+		}
+		if err = uow.OrderRepository().UpdateChecked(ctx, order); err != nil {
+			return err
+		}
+		if order.Transaction(model.ACCRUAL) == nil {
+			return nil
+		}
+		return a.tiers.Evaluate(ctx, uow, order.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if quarantined != nil {
+		reportQuarantine(ctx, quarantined)
+		return nil, quarantineError(quarantined)
+	}
+	return result, nil
+}
+
+func NewAccrualCallbackService(uow uow.UnitOfWork, tiers *TierEvaluator, policy model.TrackingPolicy) domain.AccrualCallbackService {
+	return &accrualCallbackService{uow: uow, tiers: tiers, policy: policy}
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAccrualCallbackShouldMoveOrderForward(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	sut := NewAccrualCallbackService(mockUow, NewTierEvaluator(), model.TrackingPolicy{Processing: time.Minute})
+	orderID := model.OrderID{Value: 12345678903}
+	deadLetteredAt := time.Now()
+	order := &model.Order{OrderID: orderID, Status: model.OrderStatusNEW, Error: "502 Bad Gateway",
+		ErrorClass: model.ErrorClassServer, Attempts: 10, DeadLetteredAt: &deadLetteredAt}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).Return(order, nil)
+	mockRepo.EXPECT().UpdateChecked(ctx, order).Return(nil)
+
+	result, err := sut.Apply(ctx, orderID, "PROCESSING", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPROCESSING, result.Status)
+	assert.Empty(t, result.Error)
+	assert.Zero(t, result.Attempts)
+	assert.Nil(t, result.DeadLetteredAt)
+}
+
+func TestAccrualCallbackShouldIgnoreRepeatedResults(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	sut := NewAccrualCallbackService(mockUow, NewTierEvaluator(), model.TrackingPolicy{})
+	orderID := model.OrderID{Value: 12345678903}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		}).Times(2)
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).
+		Return(&model.Order{OrderID: orderID, Status: model.OrderStatusPROCESSED}, nil).Times(2)
+
+	for _, status := range []string{"PROCESSED", "PROCESSING"} {
+		result, err := sut.Apply(ctx, orderID, status, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrderStatusPROCESSED, result.Status)
+		assert.Empty(t, result.Transactions())
+	}
+}
+
+func TestAccrualCallbackShouldQuarantineUnknownStatus(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockQuarantine := mocks.NewMockQuarantineRepository(ctrl)
+	sut := NewAccrualCallbackService(mockUow, NewTierEvaluator(), model.TrackingPolicy{})
+	orderID := model.OrderID{Value: 12345678903}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().QuarantineRepository().Return(mockQuarantine).AnyTimes()
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).
+		Return(&model.Order{OrderID: orderID, Status: model.OrderStatusNEW, Provider: model.DefaultProvider}, nil)
+	mockQuarantine.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, result *model.QuarantinedResult) (int64, error) {
+			assert.Equal(t, model.QuarantineUnknownStatus, result.Reason)
+			assert.Equal(t, "DONE", result.Status)
+			assert.Equal(t, model.DefaultProvider, result.Provider)
+			return 1, nil
+		})
+
+	_, err := sut.Apply(ctx, orderID, "DONE", nil)
+
+	assert.ErrorIs(t, err, ErrAccrualQuarantined)
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+type deadLetterService struct {
+	uow   uow.UnitOfWork
+	tiers *TierEvaluator
+}
+
+func (d *deadLetterService) List(ctx context.Context, limit, offset int) ([]*model.Order, error) {
+	return d.uow.OrderRepository().GetDeadLettered(ctx, limit, offset)
+}
+
+func (d *deadLetterService) Get(ctx context.Context, id model.OrderID) (*model.Order, error) {
+	order, err := d.load(ctx, d.uow, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.DeadLetteredAt == nil {
+		return nil, model.ErrNotDeadLettered
+	}
+	return order, nil
+}
+
+func (d *deadLetterService) Requeue(ctx context.Context, id model.OrderID) (*model.Order, error) {
+	var result *model.Order
+	err := d.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		order, err := d.load(ctx, uow, id)
+		if err != nil {
+			return err
+		}
+		if err = order.Requeue(time.Now()); err != nil {
+			return err
+		}
+		if err = uow.OrderRepository().UpdateDeadLettered(ctx, order); err != nil {
+			return err
+		}
+		result = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Resolve credits a PROCESSED order the same way order tracking does, including the tier bonus.
+func (d *deadLetterService) Resolve(ctx context.Context, id model.OrderID, status model.OrderStatus, accrual types.Decimal) (*model.Order, error) {
+	var result *model.Order
+	err := d.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		order, err := d.load(ctx, uow, id)
+		if err != nil {
+			return err
+		}
+		if err = order.Resolve(status, accrual); err != nil {
+			return err
+		}
+		if err = d.tiers.ApplyBonus(ctx, uow, order); err != nil {
+			return err
+		}
+		if err = uow.OrderRepository().UpdateDeadLettered(ctx, order); err != nil {
+			return err
+		}
+		result = order
+		if order.Transaction(model.ACCRUAL) == nil {
+			return nil
+		}
+		return d.tiers.Evaluate(ctx, uow, order.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (d *deadLetterService) load(ctx context.Context, uow uow.UnitOfWork, id model.OrderID) (*model.Order, error) {
+	order, err := uow.OrderRepository().Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+func NewDeadLetterService(uow uow.UnitOfWork, tiers *TierEvaluator) domain.DeadLetterService {
+	return &deadLetterService{uow: uow, tiers: tiers}
+}
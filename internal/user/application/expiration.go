@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"time"
+)
+
+type expirationService struct {
+	uow    uow.UnitOfWork
+	policy model.ExpirationPolicy
+	limit  int
+}
+
+func (e *expirationService) Upcoming(ctx context.Context) ([]*model.ExpiringPoints, error) {
+	if !e.policy.Enabled() {
+		return nil, nil
+	}
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lots, err := e.uow.LotRepository().GetExpiring(ctx, userID, e.policy.Cutoff(time.Now().Add(e.policy.Notice)))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*model.ExpiringPoints, len(lots))
+	for i, lot := range lots {
+		items[i] = &model.ExpiringPoints{
+			Amount:    lot.Remaining,
+			ExpiresAt: e.policy.ExpiresAt(lot.CreatedAt),
+		}
+	}
+	return items, nil
+}
+
+// Expire posts an EXPIRATION entry for every lot older than the policy allows.
+// In dry-run mode it only reports what would expire.
+func (e *expirationService) Expire(ctx context.Context, at time.Time, dryRun bool) (*model.ExpirationReport, error) {
+	report := &model.ExpirationReport{At: at, DryRun: dryRun}
+	if !e.policy.Enabled() {
+		return report, nil
+	}
+	cutoff := e.policy.Cutoff(at)
+	if dryRun {
+		offset := 0
+		for {
+			lots, err := e.uow.LotRepository().GetExpired(ctx, cutoff, e.limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			for _, lot := range lots {
+				report.Add(lot, e.policy.ExpiresAt(lot.CreatedAt))
+			}
+			if len(lots) < e.limit {
+				return report, nil
+			}
+			offset += e.limit
+		}
+	}
+	for {
+		owners, err := e.uow.LotRepository().GetExpiredOwners(ctx, cutoff, e.limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range owners {
+			expired, err := e.expireUser(ctx, userID, cutoff)
+			if err != nil {
+				return nil, err
+			}
+			for _, lot := range expired {
+				report.Add(lot, e.policy.ExpiresAt(lot.CreatedAt))
+			}
+		}
+		if len(owners) < e.limit {
+			return report, nil
+		}
+	}
+}
+
+// expireUser holds the user lock, the same one withdrawals and transfers take, so the expired points
+// can not be spent by a debit checking the balance at the same time.
+func (e *expirationService) expireUser(ctx context.Context, userID int64, cutoff time.Time) ([]*model.Lot, error) {
+	var expired []*model.Lot
+	err := e.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		if err := uow.UserRepository().Lock(ctx, userID); err != nil {
+			return err
+		}
+		lotRep := uow.LotRepository()
+		lots, err := lotRep.GetExpiredForUpdate(ctx, userID, cutoff)
+		if err != nil {
+			return err
+		}
+		expired = make([]*model.Lot, 0, len(lots))
+		for _, lot := range lots {
+			referenceID := lot.TransactionID
+			if _, err = uow.BonusMovementRepository().Insert(ctx, &model.Transaction{
+				UserID:      lot.UserID,
+				Type:        model.EXPIRATION,
+				Amount:      lot.Remaining,
+				OrderID:     lot.OrderID,
+				ReferenceID: &referenceID,
+			}); err != nil {
+				return err
+			}
+			// the report keeps the amount the lot had before it is zeroed
+			reported := *lot
+			expired = append(expired, &reported)
+			if err = lotRep.Expire(ctx, lot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func NewExpirationService(uow uow.UnitOfWork, policy model.ExpirationPolicy, limit int) domain.ExpirationService {
+	return &expirationService{uow: uow, policy: policy, limit: limit}
+}
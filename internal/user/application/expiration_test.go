@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExpireDryRunShouldReportAllPages(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockLots := mocks.NewMockLotRepository(ctrl)
+
+	at := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	lot := func(id int64, amount float64) *model.Lot {
+		return &model.Lot{
+			ID:        id,
+			UserID:    1,
+			CreatedAt: cutoff.Add(-time.Hour),
+			Remaining: types.Decimal{Decimal: decimal.NewFromFloat(amount)},
+		}
+	}
+
+	mockUow.EXPECT().LotRepository().Return(mockLots).Times(2)
+
+	mockLots.EXPECT().GetExpired(ctx, cutoff, 2, 0).Return([]*model.Lot{lot(1, 100), lot(2, 50)}, nil)
+
+	mockLots.EXPECT().GetExpired(ctx, cutoff, 2, 2).Return([]*model.Lot{lot(3, 25)}, nil)
+
+	sut := NewExpirationService(mockUow, model.ExpirationPolicy{Months: 12}, 2)
+
+	report, err := sut.Expire(ctx, at, true)
+
+	assert.NoError(t, err, "Expire should return no error")
+	assert.True(t, report.DryRun, "report should be marked as dry run")
+	assert.Len(t, report.Items, 3, "every expired lot should be reported")
+	assert.Equal(t, "175", report.Total.String(), "total should match")
+}
+
+func TestExpireWithDisabledPolicyShouldDoNothing(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+
+	sut := NewExpirationService(mockUow, model.ExpirationPolicy{}, 2)
+
+	report, err := sut.Expire(ctx, time.Now(), false)
+
+	assert.NoError(t, err, "Expire should return no error")
+	assert.Empty(t, report.Items, "nothing should expire")
+}
+
+func TestExpireShouldLockOwnerBeforeExpiringLots(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockLots := mocks.NewMockLotRepository(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockTrRepo := mocks.NewMockTransactionRepository(ctrl)
+
+	at := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	cutoff := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	lot := &model.Lot{
+		ID:            1,
+		UserID:        7,
+		TransactionID: 11,
+		CreatedAt:     cutoff.Add(-time.Hour),
+		Remaining:     types.Decimal{Decimal: decimal.NewFromInt(100)},
+	}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().LotRepository().Return(mockLots).Times(2)
+
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockUow.EXPECT().BonusMovementRepository().Return(mockTrRepo)
+
+	mockLots.EXPECT().GetExpiredOwners(ctx, cutoff, 2).Return([]int64{7}, nil)
+
+	gomock.InOrder(
+		mockURepo.EXPECT().Lock(ctx, int64(7)).Return(nil),
+		mockLots.EXPECT().GetExpiredForUpdate(ctx, int64(7), cutoff).Return([]*model.Lot{lot}, nil),
+		mockTrRepo.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, tr *model.Transaction) (int64, error) {
+			assert.Equal(t, model.EXPIRATION, tr.Type)
+			assert.Equal(t, int64(11), *tr.ReferenceID)
+			return 12, nil
+		}),
+		mockLots.EXPECT().Expire(ctx, lot).DoAndReturn(func(_ context.Context, lot *model.Lot) error {
+			lot.Remaining = types.Decimal{}
+			return nil
+		}),
+	)
+
+	sut := NewExpirationService(mockUow, model.ExpirationPolicy{Months: 12}, 2)
+
+	report, err := sut.Expire(ctx, at, false)
+
+	assert.NoError(t, err, "Expire should return no error")
+	assert.Len(t, report.Items, 1, "the expired lot should be reported")
+	assert.Equal(t, "100", report.Total.String(), "total should match")
+}
@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	exportPageSize = 500
+	// exportStaleAfter is how long an export may stay in processing before another runner picks it up again.
+	exportStaleAfter = 15 * time.Minute
+)
+
+var ErrExportNotFound = domain.NewResourceNotFound("export not found")
+
+type exportService struct {
+	uow     uow.UnitOfWork
+	storage domain.ExportStorage
+	ttl     time.Duration
+}
+
+func (e *exportService) Create(ctx context.Context, format model.ExportFormat, from, to time.Time, tt []model.TransactionType) (*model.Export, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, model.ErrInvalidPeriod
+	}
+	export := &model.Export{
+		UserID: userID,
+		Format: format,
+		From:   from,
+		To:     to,
+		Types:  tt,
+		Status: model.ExportStatusNEW,
+	}
+	if _, err = e.uow.ExportRepository().Insert(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (e *exportService) Get(ctx context.Context, id int64) (*model.Export, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	export, err := e.uow.ExportRepository().Get(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (e *exportService) Download(ctx context.Context, id int64) (*model.Export, string, error) {
+	export, err := e.Get(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if err = export.Downloadable(time.Now()); err != nil {
+		return nil, "", err
+	}
+	return export, e.storage.Path(export.File), nil
+}
+
+func (e *exportService) Process(ctx context.Context) (int, error) {
+	rep := e.uow.ExportRepository()
+	completed := 0
+	for {
+		export, err := rep.Claim(ctx, time.Now().Add(-exportStaleAfter))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return completed, nil
+			}
+			return completed, err
+		}
+		logger := logging.Logger(ctx).With(zap.Int64("export", export.ID))
+		if err = e.generate(ctx, export); err != nil {
+			logger.Warn("failed to generate export", zap.Error(err))
+			export.Fail(err, time.Now())
+		} else {
+			export.Complete(export.File, time.Now(), e.ttl)
+			completed++
+		}
+		if err = rep.Update(ctx, export); err != nil {
+			return completed, err
+		}
+	}
+}
+
+func (e *exportService) generate(ctx context.Context, export *model.Export) error {
+	rep := e.uow.BonusMovementRepository()
+	totals, err := rep.Totals(ctx, export.UserID, export.From, export.To)
+	if err != nil {
+		return err
+	}
+	export.Total = 0
+	for _, total := range totals {
+		if export.Includes(total.Type) {
+			export.Total += total.Count
+		}
+	}
+	if err = e.uow.ExportRepository().Progress(ctx, export); err != nil {
+		return err
+	}
+	file, writer, err := e.storage.Create(export)
+	if err != nil {
+		return err
+	}
+	if err = e.write(ctx, export, writer); err != nil {
+		_ = writer.Close()
+		_ = e.storage.Remove(file)
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		_ = e.storage.Remove(file)
+		return err
+	}
+	export.File = file
+	return nil
+}
+
+func (e *exportService) write(ctx context.Context, export *model.Export, writer domain.ExportWriter) error {
+	rep := e.uow.BonusMovementRepository()
+	cursor := model.StatementCursor{CreatedAt: export.From}
+	for {
+		page, err := rep.GetPage(ctx, export.UserID, cursor, export.To, export.Types, exportPageSize)
+		if err != nil {
+			return err
+		}
+		for _, tr := range page {
+			if err = writer.Write(tr); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		last := page[len(page)-1]
+		cursor = model.StatementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		export.Done += len(page)
+		if err = e.uow.ExportRepository().Progress(ctx, export); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *exportService) Cleanup(ctx context.Context, at time.Time) (int, error) {
+	rep := e.uow.ExportRepository()
+	exports, err := rep.GetExpired(ctx, at)
+	if err != nil {
+		return 0, err
+	}
+	for _, export := range exports {
+		if err = e.storage.Remove(export.File); err != nil {
+			return 0, err
+		}
+		export.Status = model.ExportStatusEXPIRED
+		if err = rep.Update(ctx, export); err != nil {
+			return 0, err
+		}
+	}
+	return len(exports), nil
+}
+
+func NewExportService(uow uow.UnitOfWork, storage domain.ExportStorage, ttl time.Duration) domain.ExportService {
+	return &exportService{
+		uow:     uow,
+		storage: storage,
+		ttl:     ttl,
+	}
+}
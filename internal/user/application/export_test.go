@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type memoryExport struct {
+	written []*model.Transaction
+	closed  bool
+}
+
+func (m *memoryExport) Write(tr *model.Transaction) error {
+	m.written = append(m.written, tr)
+	return nil
+}
+
+func (m *memoryExport) Close() error {
+	m.closed = true
+	return nil
+}
+
+type memoryStorage struct {
+	file *memoryExport
+}
+
+func (m *memoryStorage) Create(_ *model.Export) (string, domain.ExportWriter, error) {
+	return "export.csv", m.file, nil
+}
+
+func (m *memoryStorage) Path(file string) string {
+	return file
+}
+
+func (m *memoryStorage) Remove(_ string) error {
+	return nil
+}
+
+func TestProcessExportShouldWriteFilteredMovements(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockExports := mocks.NewMockExportRepository(ctrl)
+	mockTransactions := mocks.NewMockTransactionRepository(ctrl)
+
+	from := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	export := &model.Export{
+		ID:     3,
+		UserID: 1,
+		Format: model.ExportFormatCSV,
+		From:   from,
+		To:     to,
+		Types:  []model.TransactionType{model.WITHDRAWAL},
+		Status: model.ExportStatusPROCESSING,
+	}
+	amount := types.Decimal{Decimal: decimal.NewFromInt(100)}
+	page := []*model.Transaction{
+		{ID: 1, UserID: 1, Type: model.WITHDRAWAL, Amount: amount, CreatedAt: from.Add(time.Hour)},
+		{ID: 2, UserID: 1, Type: model.WITHDRAWAL, Amount: amount, CreatedAt: from.Add(2 * time.Hour)},
+	}
+
+	mockUow.EXPECT().ExportRepository().Return(mockExports).AnyTimes()
+	mockUow.EXPECT().BonusMovementRepository().Return(mockTransactions).AnyTimes()
+
+	gomock.InOrder(
+		mockExports.EXPECT().Claim(ctx, gomock.Any()).Return(export, nil),
+		mockExports.EXPECT().Claim(ctx, gomock.Any()).Return(nil, pgx.ErrNoRows),
+	)
+	mockTransactions.EXPECT().Totals(ctx, int64(1), from, to).Return([]*model.StatementTotal{
+		{Type: model.ACCRUAL, Amount: amount, Count: 5},
+		{Type: model.WITHDRAWAL, Amount: amount, Count: 2},
+	}, nil)
+	mockExports.EXPECT().Progress(ctx, export).Return(nil)
+	mockTransactions.EXPECT().GetPage(ctx, int64(1), model.StatementCursor{CreatedAt: from}, to, export.Types, exportPageSize).
+		Return(page, nil)
+	mockExports.EXPECT().Update(ctx, export).Return(nil)
+
+	file := &memoryExport{}
+	sut := NewExportService(mockUow, &memoryStorage{file: file}, time.Hour)
+
+	completed, err := sut.Process(ctx)
+
+	assert.NoError(t, err, "Process should return no error")
+	assert.Equal(t, 1, completed, "one export should be completed")
+	assert.Len(t, file.written, 2, "every movement should be written")
+	assert.True(t, file.closed, "file should be closed")
+	assert.Equal(t, model.ExportStatusDONE, export.Status)
+	assert.Equal(t, 2, export.Total, "total should count only the selected types")
+	assert.Equal(t, "export.csv", export.File)
+	assert.NotNil(t, export.ExpiresAt, "download link should expire")
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
 	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/uow"
 	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
 )
 
 var (
@@ -17,15 +19,25 @@ var (
 )
 
 type orderService struct {
-	uow uow.UnitOfWork
+	uow     uow.UnitOfWork
+	policy  domain.WithdrawalPolicy
+	routing model.ProviderRouting
 }
 
-func (o *orderService) Upload(ctx context.Context, orderID model.OrderID) (bool, error) {
+func (o *orderService) Upload(ctx context.Context, orderID model.OrderID, provider string) (bool, error) {
 	userID, err := auth.User(ctx)
 	if err != nil {
 		return false, err
 	}
-	orderRep := o.uow.OrderRepository()
+	return o.upload(ctx, o.uow, userID, orderID, provider)
+}
+
+func (o *orderService) upload(ctx context.Context, uow uow.UnitOfWork, userID int64, orderID model.OrderID, provider string) (bool, error) {
+	provider, err := o.routing.Route(orderID, provider)
+	if err != nil {
+		return false, err
+	}
+	orderRep := uow.OrderRepository()
 	ord, err := orderRep.Get(ctx, orderID)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return false, err
@@ -37,13 +49,19 @@ func (o *orderService) Upload(ctx context.Context, orderID model.OrderID) (bool,
 		return false, nil
 	}
 	_, err = orderRep.Insert(ctx, &model.Order{
-		OrderID: orderID,
-		UserID:  userID,
-		Status:  model.OrderStatusNEW,
+		OrderID:  orderID,
+		UserID:   userID,
+		Status:   model.OrderStatusNEW,
+		Provider: provider,
 	})
 	if err != nil {
 		return false, err
 	}
+	// the order is tracked by the next scheduled run anyway, a lost notification only delays it
+	if err = orderRep.NotifyUploaded(ctx, orderID); err != nil {
+		logging.Logger(ctx).Warn("failed to notify about the uploaded order",
+			zap.String("order", orderID.String()), zap.Error(err))
+	}
 	return true, nil
 }
 
@@ -60,37 +78,52 @@ func (o *orderService) List(ctx context.Context) ([]*model.Order, error) {
 	return orders, nil
 }
 
-func (o *orderService) Withdraw(ctx context.Context, orderID model.OrderID, sum types.Decimal) error {
+// Withdraw runs in one transaction holding the user lock, the same one transfers take, so concurrent movements
+// of the user are counted by the policy caps and the balance check. A rejected withdrawal leaves no order behind.
+func (o *orderService) Withdraw(ctx context.Context, orderID model.OrderID, sum types.Decimal, orderTotal *types.Decimal) error {
 	userID, err := auth.User(ctx)
 	if err != nil {
 		return err
 	}
-	if _, err = o.Upload(ctx, orderID); err != nil && !errors.Is(err, ErrOrderExistsWithAnotherUser) {
-		return err
-	}
-	orderRep := o.uow.OrderRepository()
-	ord, err := orderRep.Get(ctx, orderID)
-	if err != nil {
-		return err
-	}
-	userRep := o.uow.UserRepository()
-	bal, err := userRep.GetBonusBalanceByUserID(ctx, userID)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
-		return err
-	}
-	if bal == nil {
-		bal = &model.BonusBalance{}
+	if !sum.IsPositive() {
+		return model.ErrWithdrawalAmount
 	}
-	if bal.Current.Cmp(sum) < 0 {
-		return ErrNegativeBalance
-	}
-	ord.AddTransaction(model.WITHDRAWAL, sum)
-	if err = orderRep.Update(ctx, ord); err != nil {
-		return err
-	}
-	return nil
+	return o.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		userRep := uow.UserRepository()
+		if err := userRep.Lock(ctx, userID); err != nil {
+			return err
+		}
+		if _, err := o.upload(ctx, uow, userID, orderID, ""); err != nil && !errors.Is(err, ErrOrderExistsWithAnotherUser) {
+			return err
+		}
+		orderRep := uow.OrderRepository()
+		// the row stays locked until the withdrawal is saved, so a tracking result can not be written over
+		ord, err := orderRep.GetForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if o.policy != nil {
+			if err = o.policy.Check(ctx, userID, sum, orderTotal); err != nil {
+				return err
+			}
+		}
+		bal, err := userRep.GetBonusBalanceByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if bal == nil {
+			bal = &model.BonusBalance{}
+		}
+		if bal.Current.Cmp(sum) < 0 {
+			return ErrNegativeBalance
+		}
+		ord.AddTransaction(model.WITHDRAWAL, sum)
+		return orderRep.Update(ctx, ord)
+	})
 }
 
-func NewOrderService(uow uow.UnitOfWork) domain.OrderService {
-	return &orderService{uow: uow}
+// NewOrderService creates the service, a nil policy leaves withdrawals limited by the balance only
+// and a zero routing sends every order to model.DefaultProvider.
+func NewOrderService(uow uow.UnitOfWork, policy domain.WithdrawalPolicy, routing model.ProviderRouting) domain.OrderService {
+	return &orderService{uow: uow, policy: policy, routing: routing}
 }
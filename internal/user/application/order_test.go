@@ -5,6 +5,7 @@ import (
 	"github.com/DimKa163/gophermart/internal/shared/auth"
 	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
 	"github.com/DimKa163/gophermart/internal/user/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/jackc/pgx/v5"
@@ -24,9 +25,10 @@ func TestUploadNewOrderShouldSuccess(t *testing.T) {
 	number := "12345678903"
 	orderID, _ := model.NewOrderID(number)
 	ord := &model.Order{
-		OrderID: orderID,
-		UserID:  1,
-		Status:  model.OrderStatusNEW,
+		OrderID:  orderID,
+		UserID:   1,
+		Status:   model.OrderStatusNEW,
+		Provider: model.DefaultProvider,
 	}
 	mockUow.EXPECT().OrderRepository().Return(mockRepo)
 
@@ -34,9 +36,11 @@ func TestUploadNewOrderShouldSuccess(t *testing.T) {
 
 	mockRepo.EXPECT().Insert(ctx, ord).Return(orderID, nil)
 
-	sut := NewOrderService(mockUow)
+	mockRepo.EXPECT().NotifyUploaded(ctx, orderID).Return(nil)
 
-	result, err := sut.Upload(ctx, orderID)
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
+
+	result, err := sut.Upload(ctx, orderID, "")
 
 	assert.NoError(t, err, "Upload should return no error")
 	assert.True(t, result, "Upload should return result true")
@@ -61,9 +65,9 @@ func TestUploadNotNewOrderShouldReturnSuccess(t *testing.T) {
 
 	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil)
 
-	sut := NewOrderService(mockUow)
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
 
-	result, err := sut.Upload(ctx, orderID)
+	result, err := sut.Upload(ctx, orderID, "")
 
 	assert.NoError(t, err, "Upload should return no error")
 	assert.False(t, result, "Upload should return result true")
@@ -88,14 +92,58 @@ func TestUploadNotNewOrderShouldReturnError(t *testing.T) {
 
 	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil)
 
-	sut := NewOrderService(mockUow)
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
 
-	result, err := sut.Upload(ctx, orderID)
+	result, err := sut.Upload(ctx, orderID, "")
 
 	assert.ErrorIs(t, ErrOrderExistsWithAnotherUser, err, "Upload should return error for another user")
 	assert.False(t, result, "Upload should return result true")
 }
 
+func TestUploadOrderShouldStoreRoutedProvider(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+
+	ctx = auth.SetUser(ctx, 1)
+	orderID, _ := model.NewOrderID("12345678903")
+	mockUow.EXPECT().OrderRepository().Return(mockRepo)
+	mockRepo.EXPECT().Get(ctx, orderID).Return(nil, pgx.ErrNoRows)
+	mockRepo.EXPECT().Insert(ctx, &model.Order{
+		OrderID:  orderID,
+		UserID:   1,
+		Status:   model.OrderStatusNEW,
+		Provider: "brand-a",
+	}).Return(orderID, nil)
+	mockRepo.EXPECT().NotifyUploaded(ctx, orderID).Return(nil)
+
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{
+		Providers: []string{"brand-a"},
+		Routes:    []model.ProviderRoute{{Prefix: "1234", Provider: "brand-a"}},
+	})
+
+	result, err := sut.Upload(ctx, orderID, "")
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+}
+
+func TestUploadOrderWithUnknownProviderShouldReturnError(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	orderID, _ := model.NewOrderID("12345678903")
+
+	sut := NewOrderService(mocks.NewMockUnitOfWork(ctrl), nil, model.ProviderRouting{})
+
+	result, err := sut.Upload(ctx, orderID, "brand-a")
+
+	assert.ErrorIs(t, err, model.ErrUnknownProvider)
+	assert.False(t, result)
+}
+
 func TestWithdrawWithBalanceShouldSuccess(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -115,19 +163,28 @@ func TestWithdrawWithBalanceShouldSuccess(t *testing.T) {
 
 	bal := &model.BonusBalance{UserID: 1, Current: types.Decimal{Decimal: decimal.NewFromFloat32(500.00)}, Withdrawn: types.Decimal{Decimal: decimal.NewFromFloat32(0.00)}}
 
-	mockUow.EXPECT().OrderRepository().Return(mockRepo)
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil)
+
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).Times(2)
 
 	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil)
 
-	mockUow.EXPECT().UserRepository().Return(mockURepo)
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).Return(ord, nil)
 
 	mockURepo.EXPECT().GetBonusBalanceByUserID(ctx, int64(1)).Return(bal, nil)
 
 	mockRepo.EXPECT().Update(ctx, ord).Return(nil)
 
-	sut := NewOrderService(mockUow)
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
 
-	err := sut.Withdraw(ctx, orderID, types.Decimal{Decimal: decimal.NewFromFloat32(100.00)})
+	err := sut.Withdraw(ctx, orderID, types.Decimal{Decimal: decimal.NewFromFloat32(100.00)}, nil)
 
 	assert.NoError(t, err, "Withdraw should return no error")
 }
@@ -151,17 +208,130 @@ func TestWithdrawWithoutBalanceShouldReturnError(t *testing.T) {
 
 	bal := &model.BonusBalance{UserID: 1, Current: types.Decimal{Decimal: decimal.NewFromFloat32(50.00)}, Withdrawn: types.Decimal{Decimal: decimal.NewFromFloat32(0.00)}}
 
-	mockUow.EXPECT().OrderRepository().Return(mockRepo).MaxTimes(2)
-
-	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil).MaxTimes(2)
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
 
 	mockUow.EXPECT().UserRepository().Return(mockURepo)
 
+	mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil)
+
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).Times(2)
+
+	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil)
+
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).Return(ord, nil)
+
 	mockURepo.EXPECT().GetBonusBalanceByUserID(ctx, int64(1)).Return(bal, nil)
 
-	sut := NewOrderService(mockUow)
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
 
-	err := sut.Withdraw(ctx, orderID, types.Decimal{Decimal: decimal.NewFromFloat32(100.00)})
+	err := sut.Withdraw(ctx, orderID, types.Decimal{Decimal: decimal.NewFromFloat32(100.00)}, nil)
 
 	assert.ErrorIs(t, ErrNegativeBalance, err, "Withdraw should return error")
 }
+
+func TestWithdrawBreakingPolicyShouldReturnViolation(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockRules := mocks.NewMockWithdrawalRuleRepository(ctrl)
+	ctx = auth.SetUser(ctx, 1)
+	orderID, _ := model.NewOrderID("12345678903")
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			// the new order is rolled back together with the rejected withdrawal
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).Times(2)
+
+	mockRepo.EXPECT().Get(ctx, orderID).Return(nil, pgx.ErrNoRows)
+
+	mockRepo.EXPECT().Insert(ctx, gomock.Any()).Return(orderID, nil)
+
+	mockRepo.EXPECT().NotifyUploaded(ctx, orderID).Return(nil)
+
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).Return(&model.Order{OrderID: orderID, UserID: 1, Status: model.OrderStatusNEW}, nil)
+
+	mockUow.EXPECT().WithdrawalRuleRepository().Return(mockRules).Times(2)
+
+	gomock.InOrder(
+		mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil),
+		mockRules.EXPECT().GetForUser(ctx, int64(1)).Return(nil, nil),
+		mockRules.EXPECT().Usage(ctx, int64(1), gomock.Any(), gomock.Any()).Return(&model.WithdrawalUsage{
+			Day: types.Decimal{Decimal: decimal.NewFromInt(250)},
+		}, nil),
+	)
+
+	policy := NewWithdrawalPolicy(mockUow, model.WithdrawalRules{DailyCap: types.Decimal{Decimal: decimal.NewFromInt(300)}})
+
+	sut := NewOrderService(mockUow, policy, model.ProviderRouting{})
+
+	err := sut.Withdraw(ctx, orderID, types.Decimal{Decimal: decimal.NewFromInt(100)}, nil)
+
+	var violation *model.PolicyViolation
+	assert.ErrorAs(t, err, &violation, "Withdraw should return policy violation")
+	assert.Equal(t, model.CodeDailyCapExceeded, violation.Code)
+}
+
+func TestWithdrawShouldLockUserBeforeCheckingPolicy(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockPolicy := mocks.NewMockWithdrawalPolicy(ctrl)
+	ctx = auth.SetUser(ctx, 1)
+	orderID, _ := model.NewOrderID("12345678903")
+	sum := types.Decimal{Decimal: decimal.NewFromInt(100)}
+	ord := &model.Order{OrderID: orderID, UserID: 1, Status: model.OrderStatusNEW}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).Times(2)
+	mockRepo.EXPECT().Get(ctx, orderID).Return(ord, nil)
+
+	mockRepo.EXPECT().GetForUpdate(ctx, orderID).Return(ord, nil)
+	gomock.InOrder(
+		mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil),
+		mockPolicy.EXPECT().Check(ctx, int64(1), sum, nil).Return(nil),
+		mockURepo.EXPECT().GetBonusBalanceByUserID(ctx, int64(1)).Return(&model.BonusBalance{
+			UserID:  1,
+			Current: types.Decimal{Decimal: decimal.NewFromInt(500)},
+		}, nil),
+		mockRepo.EXPECT().Update(ctx, ord).Return(nil),
+	)
+
+	sut := NewOrderService(mockUow, mockPolicy, model.ProviderRouting{})
+
+	err := sut.Withdraw(ctx, orderID, sum, nil)
+
+	assert.NoError(t, err, "Withdraw should return no error")
+}
+
+func TestWithdrawZeroSumShouldReturnError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	ctx = auth.SetUser(ctx, 1)
+	orderID, _ := model.NewOrderID("12345678903")
+
+	sut := NewOrderService(mockUow, nil, model.ProviderRouting{})
+
+	err := sut.Withdraw(ctx, orderID, types.Decimal{}, nil)
+
+	assert.ErrorIs(t, err, model.ErrWithdrawalAmount, "Withdraw should reject a zero sum")
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+var (
+	ErrPromoCodeNotFound = domain.NewResourceNotFound("promo code not found")
+	ErrPromoCodeExists   = &domain.ResourceAlreadyExists{Message: "promo code already exists"}
+)
+
+type promoService struct {
+	uow uow.UnitOfWork
+}
+
+func (p *promoService) Create(ctx context.Context, promo *model.PromoCode) (*model.PromoCode, error) {
+	promo.Code = model.NormalizePromoCode(promo.Code)
+	if err := promo.Validate(); err != nil {
+		return nil, err
+	}
+	rep := p.uow.PromoCodeRepository()
+	exists, err := rep.Exists(ctx, promo.Code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrPromoCodeExists
+	}
+	if _, err = rep.Insert(ctx, promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+func (p *promoService) Generate(ctx context.Context, template *model.PromoCode, prefix string, count int) ([]*model.PromoCode, error) {
+	if count <= 0 {
+		return nil, model.ErrPromoCodeInvalid
+	}
+	codes := make([]*model.PromoCode, 0, count)
+	err := p.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		rep := uow.PromoCodeRepository()
+		for i := 0; i < count; i++ {
+			promo, err := model.NewGiftCode(template, prefix)
+			if err != nil {
+				return err
+			}
+			if err = promo.Validate(); err != nil {
+				return err
+			}
+			if _, err = rep.Insert(ctx, promo); err != nil {
+				return err
+			}
+			codes = append(codes, promo)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (p *promoService) List(ctx context.Context, limit, offset int) ([]*model.PromoCode, error) {
+	return p.uow.PromoCodeRepository().GetAll(ctx, limit, offset)
+}
+
+func (p *promoService) Redeem(ctx context.Context, code string) (*model.Transaction, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result *model.Transaction
+	err = p.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		rep := uow.PromoCodeRepository()
+		// the row lock serializes concurrent redemptions of the same code
+		promo, err := rep.GetForUpdate(ctx, model.NormalizePromoCode(code))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPromoCodeNotFound
+			}
+			return err
+		}
+		redeemed, err := rep.UserRedemptions(ctx, promo.ID, userID)
+		if err != nil {
+			return err
+		}
+		if err = promo.CanRedeem(time.Now(), redeemed); err != nil {
+			return err
+		}
+		credit := promo.Credit(userID)
+		if _, err = uow.BonusMovementRepository().Insert(ctx, credit); err != nil {
+			return err
+		}
+		if err = rep.Redeem(ctx, promo, userID, credit.ID); err != nil {
+			return err
+		}
+		result = credit
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func NewPromoService(uow uow.UnitOfWork) domain.PromoService {
+	return &promoService{uow: uow}
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRedeemPromoCodeShouldCreditUser(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockPromo := mocks.NewMockPromoCodeRepository(ctrl)
+	mockTransactions := mocks.NewMockTransactionRepository(ctrl)
+
+	promo := &model.PromoCode{
+		ID:             7,
+		Code:           "WELCOME",
+		Amount:         types.Decimal{Decimal: decimal.NewFromInt(100)},
+		MaxRedemptions: 10,
+		PerUserLimit:   1,
+	}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().PromoCodeRepository().Return(mockPromo)
+	mockUow.EXPECT().BonusMovementRepository().Return(mockTransactions)
+
+	mockPromo.EXPECT().GetForUpdate(ctx, "WELCOME").Return(promo, nil)
+	mockPromo.EXPECT().UserRedemptions(ctx, int64(7), int64(1)).Return(0, nil)
+	mockTransactions.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tr *model.Transaction) (int64, error) {
+			tr.ID = 42
+			return tr.ID, nil
+		})
+	mockPromo.EXPECT().Redeem(ctx, promo, int64(1), int64(42)).Return(nil)
+
+	sut := NewPromoService(mockUow)
+
+	result, err := sut.Redeem(ctx, " welcome ")
+
+	assert.NoError(t, err, "Redeem should return no error")
+	assert.Equal(t, model.PROMO, result.Type, "transaction should be a promo credit")
+	assert.Equal(t, "100", result.Amount.String(), "amount should match the code")
+}
+
+func TestRedeemAlreadyRedeemedPromoCodeShouldFail(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockPromo := mocks.NewMockPromoCodeRepository(ctrl)
+
+	promo := &model.PromoCode{ID: 7, Code: "WELCOME", PerUserLimit: 1}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().PromoCodeRepository().Return(mockPromo)
+
+	mockPromo.EXPECT().GetForUpdate(ctx, "WELCOME").Return(promo, nil)
+	mockPromo.EXPECT().UserRedemptions(ctx, int64(7), int64(1)).Return(1, nil)
+
+	sut := NewPromoService(mockUow)
+
+	_, err := sut.Redeem(ctx, "WELCOME")
+
+	assert.ErrorIs(t, err, model.ErrPromoCodeAlreadyRedeemed)
+}
+
+func TestRedeemUnknownPromoCodeShouldReturnNotFound(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockPromo := mocks.NewMockPromoCodeRepository(ctrl)
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockUow.EXPECT().PromoCodeRepository().Return(mockPromo)
+
+	mockPromo.EXPECT().GetForUpdate(ctx, "NOPE").Return(nil, pgx.ErrNoRows)
+
+	sut := NewPromoService(mockUow)
+
+	_, err := sut.Redeem(ctx, "nope")
+
+	assert.ErrorIs(t, err, ErrPromoCodeNotFound)
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	ErrAccrualQuarantined = errors.New("accrual result quarantined")
+	ErrQuarantineNotFound = domain.NewResourceNotFound("quarantined accrual result not found")
+)
+
+// quarantineMetrics counts quarantined accrual results per reason, they are published with the other expvar metrics.
+var quarantineMetrics = expvar.NewMap("accrual_quarantine")
+
+// checkAccrual validates the result of the requested order and maps its status. A result that can not be trusted
+// is returned as quarantined instead, maxAccrual bounds the accrual on top of what the points column holds.
+func checkAccrual(order *model.Order, result *dto.Order, maxAccrual types.Decimal) (model.OrderStatus, *model.QuarantinedResult) {
+	status, reason := validateAccrual(order.OrderID.String(), result, maxAccrual)
+	if reason == "" {
+		return status, nil
+	}
+	return order.Status, &model.QuarantinedResult{
+		OrderID:  order.OrderID,
+		Provider: order.Provider,
+		Reason:   reason,
+		Number:   result.Number,
+		Status:   result.Status.String(),
+		Accrual:  result.Accrual,
+	}
+}
+
+func validateAccrual(number string, result *dto.Order, maxAccrual types.Decimal) (model.OrderStatus, model.QuarantineReason) {
+	if result.Number != number {
+		return 0, model.QuarantineNumberMismatch
+	}
+	status, ok := statusMap[result.Status]
+	if !ok {
+		return 0, model.QuarantineUnknownStatus
+	}
+	if result.Accrual == nil {
+		return status, ""
+	}
+	if status != model.OrderStatusPROCESSED {
+		return 0, model.QuarantineUnexpectedAccrual
+	}
+	if result.Accrual.IsNegative() || result.Accrual.GreaterThan(types.MaxPoints.Decimal().Decimal) ||
+		(maxAccrual.IsPositive() && result.Accrual.GreaterThan(maxAccrual.Decimal)) {
+		return 0, model.QuarantineAccrualRange
+	}
+	return status, ""
+}
+
+// reportQuarantine logs and counts a result once it is saved for review.
+func reportQuarantine(ctx context.Context, result *model.QuarantinedResult) {
+	quarantineMetrics.Add(string(result.Reason), 1)
+	logger := logging.Logger(ctx).With(
+		zap.String("order", result.OrderID.String()),
+		zap.String("provider", result.Provider),
+		zap.String("reason", string(result.Reason)),
+		zap.String("reported_number", result.Number),
+		zap.String("reported_status", result.Status))
+	if result.Accrual != nil {
+		logger = logger.With(zap.String("reported_accrual", result.Accrual.String()))
+	}
+	logger.Warn("accrual result quarantined")
+}
+
+func quarantineError(result *model.QuarantinedResult) error {
+	return fmt.Errorf("%w: %s", ErrAccrualQuarantined, result.Reason)
+}
+
+type quarantineService struct {
+	uow uow.UnitOfWork
+}
+
+func (q *quarantineService) List(ctx context.Context, limit, offset int) ([]*model.QuarantinedResult, error) {
+	return q.uow.QuarantineRepository().GetPending(ctx, limit, offset)
+}
+
+func (q *quarantineService) Get(ctx context.Context, id int64) (*model.QuarantinedResult, error) {
+	return q.load(ctx, q.uow, id)
+}
+
+func (q *quarantineService) Review(ctx context.Context, id int64, note string) (*model.QuarantinedResult, error) {
+	var result *model.QuarantinedResult
+	err := q.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		quarantined, err := q.load(ctx, uow, id)
+		if err != nil {
+			return err
+		}
+		if err = quarantined.Review(note, time.Now()); err != nil {
+			return err
+		}
+		if err = uow.QuarantineRepository().Review(ctx, quarantined); err != nil {
+			return err
+		}
+		result = quarantined
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (q *quarantineService) load(ctx context.Context, uow uow.UnitOfWork, id int64) (*model.QuarantinedResult, error) {
+	result, err := uow.QuarantineRepository().Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrQuarantineNotFound
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+func NewQuarantineService(uow uow.UnitOfWork) domain.QuarantineService {
+	return &quarantineService{uow: uow}
+}
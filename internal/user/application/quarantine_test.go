@@ -0,0 +1,171 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func accrualOf(t *testing.T, value string) *types.Decimal {
+	amount, err := types.NewDecimalFromString(value)
+	assert.NoError(t, err)
+	return &amount
+}
+
+func TestValidateAccrual(t *testing.T) {
+	maxAccrual := accrualOf(t, "1000")
+	cases := []struct {
+		name           string
+		result         dto.Order
+		expectedStatus model.OrderStatus
+		expectedReason model.QuarantineReason
+	}{
+		{
+			name:           "processed with accrual",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED, Accrual: accrualOf(t, "500.5")},
+			expectedStatus: model.OrderStatusPROCESSED,
+		},
+		{
+			name:           "processed without accrual",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED},
+			expectedStatus: model.OrderStatusPROCESSED,
+		},
+		{
+			name:           "registered",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusREGISTERED},
+			expectedStatus: model.OrderStatusNEW,
+		},
+		{
+			name:           "another order",
+			result:         dto.Order{Number: "79927398713", Status: dto.StatusPROCESSED, Accrual: accrualOf(t, "500")},
+			expectedReason: model.QuarantineNumberMismatch,
+		},
+		{
+			name:           "unknown status",
+			result:         dto.Order{Number: "12345678903", Status: "DONE"},
+			expectedReason: model.QuarantineUnknownStatus,
+		},
+		{
+			name:           "accrual before processed",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusPROCESSING, Accrual: accrualOf(t, "10")},
+			expectedReason: model.QuarantineUnexpectedAccrual,
+		},
+		{
+			name:           "negative accrual",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED, Accrual: accrualOf(t, "-1")},
+			expectedReason: model.QuarantineAccrualRange,
+		},
+		{
+			name:           "accrual above maximum",
+			result:         dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED, Accrual: accrualOf(t, "1000.01")},
+			expectedReason: model.QuarantineAccrualRange,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, reason := validateAccrual("12345678903", &c.result, *maxAccrual)
+
+			assert.Equal(t, c.expectedReason, reason)
+			if c.expectedReason == "" {
+				assert.Equal(t, c.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestValidateAccrualShouldKeepTheColumnLimitWithoutMaximum(t *testing.T) {
+	result := dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED, Accrual: accrualOf(t, "100000000")}
+
+	_, reason := validateAccrual("12345678903", &result, types.Decimal{})
+
+	assert.Equal(t, model.QuarantineAccrualRange, reason)
+}
+
+type staticAccrualClient struct {
+	result dto.Order
+}
+
+func (c *staticAccrualClient) Order(context.Context, string) (*dto.Order, error) {
+	result := c.result
+	return &result, nil
+}
+
+func TestTrackOrderHandlerShouldQuarantineSuspiciousResults(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockQuarantine := mocks.NewMockQuarantineRepository(ctrl)
+	policy := model.TrackingPolicy{ErrorBase: time.Minute, Lease: time.Minute}
+	client := &staticAccrualClient{result: dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED,
+		Accrual: accrualOf(t, "-500")}}
+	providers, err := accrual.NewProviders(map[string]accrual.AccrualClient{model.DefaultProvider: client})
+	assert.NoError(t, err)
+	handler := NewTrackOrderHandler(mockUow, NewTrackOrderProcessor(providers, 1, types.Decimal{}), NewTierEvaluator(),
+		policy, "node-1")
+	order := &model.Order{OrderID: model.OrderID{Value: 12345678903}, Status: model.OrderStatusPROCESSING,
+		Provider: model.DefaultProvider}
+
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().QuarantineRepository().Return(mockQuarantine).AnyTimes()
+	mockRepo.EXPECT().Claim(ctx, gomock.Any(), 10, model.OrderStatusNEW, model.OrderStatusPROCESSING).
+		Return([]*model.Order{order}, nil)
+	mockUow.EXPECT().BeginTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+	mockQuarantine.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, result *model.QuarantinedResult) (int64, error) {
+			assert.Equal(t, model.QuarantineAccrualRange, result.Reason)
+			assert.Equal(t, "-500", result.Accrual.String())
+			return 1, nil
+		})
+	mockRepo.EXPECT().UpdateLeased(gomock.Any(), order, gomock.Any()).Return(nil)
+
+	report, err := handler.Handle(ctx, &TrackOrderCommand{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Quarantined)
+	assert.Equal(t, model.OrderStatusPROCESSING, order.Status)
+	assert.Equal(t, model.ErrorClassQuarantined, order.ErrorClass)
+	assert.Equal(t, 1, order.Attempts)
+	assert.Empty(t, order.Transactions())
+}
+
+func TestQuarantineReviewShouldOnlyHappenOnce(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockQuarantine := mocks.NewMockQuarantineRepository(ctrl)
+	sut := NewQuarantineService(mockUow)
+	reviewedAt := time.Now()
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		}).Times(2)
+	mockUow.EXPECT().QuarantineRepository().Return(mockQuarantine).AnyTimes()
+	mockQuarantine.EXPECT().Get(ctx, int64(1)).Return(&model.QuarantinedResult{ID: 1}, nil)
+	mockQuarantine.EXPECT().Get(ctx, int64(2)).Return(&model.QuarantinedResult{ID: 2, ReviewedAt: &reviewedAt}, nil)
+	mockQuarantine.EXPECT().Review(ctx, gomock.Any()).Return(nil)
+
+	result, err := sut.Review(ctx, 1, "provider confirmed a bug")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.ReviewedAt)
+	assert.Equal(t, "provider confirmed a bug", result.Note)
+
+	_, err = sut.Review(ctx, 2, "again")
+
+	assert.ErrorIs(t, err, model.ErrQuarantineReviewed)
+}
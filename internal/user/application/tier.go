@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// TierEvaluator grants the tier multiplier on accruals and moves users between tiers.
+type TierEvaluator struct{}
+
+func NewTierEvaluator() *TierEvaluator {
+	return &TierEvaluator{}
+}
+
+// ApplyBonus adds a BONUS transaction on top of a pending accrual according to the user's current tier.
+func (e *TierEvaluator) ApplyBonus(ctx context.Context, uow uow.UnitOfWork, order *model.Order) error {
+	accrual := order.Transaction(model.ACCRUAL)
+	if accrual == nil {
+		return nil
+	}
+	tier, err := uow.TierRepository().GetByUser(ctx, order.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if bonus := tier.Bonus(accrual.Amount); bonus.IsPositive() {
+		order.AddTransaction(model.BONUS, bonus)
+	}
+	return nil
+}
+
+// Evaluate recalculates the tier from the rolling accrued points and records a change in the tier history.
+func (e *TierEvaluator) Evaluate(ctx context.Context, uow uow.UnitOfWork, userID int64) error {
+	rep := uow.TierRepository()
+	now := time.Now()
+	accrued, err := rep.RollingAccrued(ctx, userID, now.AddDate(0, -model.TierWindowMonths, 0))
+	if err != nil {
+		return err
+	}
+	tiers, err := rep.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := rep.GetByUser(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	next := model.ResolveTier(tiers, accrued)
+	if tierID(current) == tierID(next) {
+		return nil
+	}
+	var previousID, nextID *int
+	if current != nil {
+		previousID = &current.ID
+	}
+	if next != nil {
+		nextID = &next.ID
+	}
+	return rep.Change(ctx, &model.TierHistory{
+		UserID:         userID,
+		ChangedAt:      now,
+		PreviousTierID: previousID,
+		TierID:         nextID,
+		RollingAccrued: accrued,
+	})
+}
+
+func tierID(tier *model.Tier) int {
+	if tier == nil {
+		return 0
+	}
+	return tier.ID
+}
+
+type tierService struct {
+	uow uow.UnitOfWork
+}
+
+func (t *tierService) Progress(ctx context.Context) (*model.TierProgress, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep := t.uow.TierRepository()
+	accrued, err := rep.RollingAccrued(ctx, userID, time.Now().AddDate(0, -model.TierWindowMonths, 0))
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := rep.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := rep.GetByUser(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return model.NewTierProgress(tiers, current, accrued), nil
+}
+
+func (t *tierService) History(ctx context.Context) ([]*model.TierHistory, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.uow.TierRepository().GetHistory(ctx, userID)
+}
+
+func NewTierService(uow uow.UnitOfWork) domain.TierService {
+	return &tierService{uow: uow}
+}
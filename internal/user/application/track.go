@@ -2,11 +2,17 @@ package application
 
 import (
 	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/uow"
 	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual"
 	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"go.uber.org/zap"
+	"net/http"
 	"sync"
+	"time"
 )
 
 var statusMap map[dto.OrderStatus]model.OrderStatus = map[dto.OrderStatus]model.OrderStatus{
@@ -21,47 +27,197 @@ type TrackOrderCommand struct {
 }
 
 type TrackOrderHandler struct {
-	uow uow.UnitOfWork
+	uow    uow.UnitOfWork
+	tiers  *TierEvaluator
+	policy model.TrackingPolicy
+	owner  string
 	*TrackOrderProcessor
 }
 
-func NewTrackOrderHandler(uow uow.UnitOfWork, processor *TrackOrderProcessor) *TrackOrderHandler {
-	return &TrackOrderHandler{uow: uow, TrackOrderProcessor: processor}
+// NewTrackOrderHandler creates a handler that leases orders under the owner name, unique per running instance.
+func NewTrackOrderHandler(uow uow.UnitOfWork, processor *TrackOrderProcessor, tiers *TierEvaluator, policy model.TrackingPolicy, owner string) *TrackOrderHandler {
+	return &TrackOrderHandler{uow: uow, TrackOrderProcessor: processor, tiers: tiers, policy: policy, owner: owner}
 }
 
-func (handler *TrackOrderHandler) Handle(ctx context.Context, command *TrackOrderCommand) error {
-	return handler.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
-		orderRep := uow.OrderRepository()
-		offset := 0
-		items, err := orderRep.GetForUpdate(ctx, command.Limit, offset, model.OrderStatusNEW, model.OrderStatusPROCESSING)
+// TrackOrderReport sums up a tracking run.
+type TrackOrderReport struct {
+	// Checked counts the orders the accrual service answered for, Failed those checks that ended with an error.
+	Checked      int
+	Failed       int
+	DeadLettered int
+	// Quarantined counts the results held back for review instead of being applied.
+	Quarantined int
+	// Released counts the orders left for a later run, e.g. while the accrual service throttles requests.
+	Released int
+	// AlreadyCredited counts the checked orders whose accrual had been saved before, they are not credited again.
+	AlreadyCredited int
+	// Transitions counts status changes like "NEW->PROCESSING".
+	Transitions map[string]int
+}
+
+func (r *TrackOrderReport) transition(from, to model.OrderStatus) {
+	if r.Transitions == nil {
+		r.Transitions = make(map[string]int)
+	}
+	r.Transitions[from.String()+"->"+to.String()]++
+}
+
+// Handle claims due orders batch by batch. No transaction is open while the accrual service is called,
+// every checked order is saved in a short transaction of its own as long as the lease still holds.
+func (handler *TrackOrderHandler) Handle(ctx context.Context, command *TrackOrderCommand) (TrackOrderReport, error) {
+	var report TrackOrderReport
+	for !handler.Paused() {
+		now := time.Now()
+		items, err := handler.uow.OrderRepository().Claim(ctx, model.Lease{
+			Owner: handler.owner,
+			At:    now,
+			Until: now.Add(handler.policy.Lease),
+		}, command.Limit, model.OrderStatusNEW, model.OrderStatusPROCESSING)
 		if err != nil {
-			return err
+			return report, err
 		}
-		for len(items) > 0 {
-			ch := handler.Process(ctx, items)
-			for it := range ch {
-				err = orderRep.Update(ctx, it)
-				if err != nil {
-					return err
-				}
-			}
-			offset += command.Limit
-			items, err = orderRep.GetForUpdate(ctx, command.Limit, offset, model.OrderStatusNEW, model.OrderStatusPROCESSING)
-			if err != nil {
-				return err
-			}
+		if len(items) == 0 {
+			return report, nil
+		}
+		skipped, err := handler.handleBatch(ctx, items, &report)
+		if err != nil {
+			return report, err
+		}
+		// skipped orders would be claimed again right away, the rest of the backlog waits for the next run
+		if skipped > 0 || len(items) < command.Limit {
+			return report, nil
+		}
+	}
+	return report, nil
+}
+
+func (handler *TrackOrderHandler) handleBatch(ctx context.Context, items []*model.Order, report *TrackOrderReport) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	checked := make(map[int64]bool, len(items))
+	previous := make(map[int64]model.OrderStatus, len(items))
+	for _, it := range items {
+		previous[it.OrderID.Value] = it.Status
+	}
+	for it := range handler.Process(ctx, items) {
+		checked[it.OrderID.Value] = true
+		it.Schedule(handler.policy, time.Now())
+		err := handler.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+			return handler.update(ctx, uow, it)
+		})
+		if errors.Is(err, model.ErrAlreadyCredited) {
+			// the accrual was saved before, e.g. by a callback or a run whose result was not acknowledged
+			logging.Logger(ctx).Info("order is already credited, saved without crediting it again",
+				zap.String("order", it.OrderID.String()))
+			report.AlreadyCredited++
+			err = handler.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+				return handler.settle(ctx, uow, it)
+			})
+		}
+		if errors.Is(err, model.ErrLeaseLost) {
+			logging.Logger(ctx).Warn("order lease expired before the result was saved",
+				zap.String("order", it.OrderID.String()))
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		report.Checked++
+		if it.Error != "" {
+			report.Failed++
+		}
+		if it.Quarantined != nil {
+			report.Quarantined++
+			reportQuarantine(ctx, it.Quarantined)
+		}
+		if from := previous[it.OrderID.Value]; from != it.Status {
+			report.transition(from, it.Status)
+		}
+		if it.DeadLetteredAt != nil {
+			report.DeadLettered++
+			logging.Logger(ctx).Warn("order dead-lettered after repeated failures",
+				zap.String("order", it.OrderID.String()),
+				zap.Int("attempts", it.Attempts),
+				zap.String("class", string(it.ErrorClass)),
+				zap.String("error", it.Error))
+		}
+	}
+	var skipped []model.OrderID
+	for _, it := range items {
+		if !checked[it.OrderID.Value] {
+			skipped = append(skipped, it.OrderID)
+		}
+	}
+	if len(skipped) == 0 {
+		return 0, nil
+	}
+	report.Released += len(skipped)
+	return len(skipped), handler.uow.OrderRepository().Release(ctx, skipped, handler.owner)
+}
+
+func (handler *TrackOrderHandler) update(ctx context.Context, uow uow.UnitOfWork, order *model.Order) error {
+	if order.Quarantined != nil {
+		if _, err := uow.QuarantineRepository().Insert(ctx, order.Quarantined); err != nil {
+			return err
 		}
+	}
+	if err := handler.tiers.ApplyBonus(ctx, uow, order); err != nil {
+		return err
+	}
+	if err := uow.OrderRepository().UpdateLeased(ctx, order, model.Lease{Owner: handler.owner, At: time.Now()}); err != nil {
+		return err
+	}
+	if order.Transaction(model.ACCRUAL) == nil {
 		return nil
-	})
+	}
+	return handler.tiers.Evaluate(ctx, uow, order.UserID)
+}
+
+// settle saves the order credited elsewhere with the reported status so that it leaves the queue,
+// the stored accrual is kept and the pending transactions are left out.
+func (handler *TrackOrderHandler) settle(ctx context.Context, uow uow.UnitOfWork, order *model.Order) error {
+	orderRep := uow.OrderRepository()
+	stored, err := orderRep.Get(ctx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	stored.Status = order.Status
+	stored.Error = ""
+	stored.Schedule(handler.policy, time.Now())
+	return orderRep.UpdateLeased(ctx, stored, model.Lease{Owner: handler.owner, At: time.Now()})
 }
 
 type TrackOrderProcessor struct {
-	accrualCl accrual.AccrualClient
+	providers   *accrual.Providers
+	concurrency int
+	maxAccrual  types.Decimal
+	mu          sync.Mutex
+	pausedUntil map[string]time.Time
 }
 
+// Paused reports whether every accrual provider asked to stop sending requests for now.
+func (p *TrackOrderProcessor) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for _, name := range p.providers.Names() {
+		if !now.Before(p.pausedUntil[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *TrackOrderProcessor) pause(provider string, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pausedUntil[provider] = until
+}
+
+// Process polls the accrual service for the orders with at most concurrency requests in flight.
 func (p *TrackOrderProcessor) Process(ctx context.Context, orders []*model.Order) <-chan *model.Order {
 	inputCh := p.iterate(ctx, orders)
-	channels := p.fanOut(ctx, len(orders), inputCh, p.processOrder)
+	channels := p.fanOut(ctx, min(p.concurrency, len(orders)), inputCh, p.processOrder)
 	return p.fanIn(ctx, channels...)
 }
 
@@ -80,11 +236,11 @@ func (p *TrackOrderProcessor) iterate(ctx context.Context, orders []*model.Order
 	return inputCh
 }
 
-func (p *TrackOrderProcessor) fanOut(ctx context.Context, len int, inputCh <-chan *model.Order,
+func (p *TrackOrderProcessor) fanOut(ctx context.Context, workers int, inputCh <-chan *model.Order,
 	fn func(ctx context.Context,
 		inputCh <-chan *model.Order) <-chan *model.Order) []<-chan *model.Order {
-	channels := make([]<-chan *model.Order, len)
-	for i := 0; i < len; i++ {
+	channels := make([]<-chan *model.Order, workers)
+	for i := 0; i < workers; i++ {
 		channels[i] = fn(ctx, inputCh)
 	}
 	return channels
@@ -123,17 +279,30 @@ func (p *TrackOrderProcessor) processOrder(ctx context.Context, inputCh <-chan *
 			if data == nil {
 				continue
 			}
+			provider, client := p.providers.Client(data.Provider)
+			or, err := client.Order(ctx, data.OrderID.String())
+			var throttled *accrual.TooManyRequestsError
+			if errors.As(err, &throttled) {
+				// the order goes back to the queue as it is and is picked up again by a later run
+				p.pause(provider, throttled.Until)
+				continue
+			}
+			if errors.Is(err, accrual.ErrUnavailable) {
+				continue
+			}
 			data.Error = ""
-			or, err := p.accrualCl.Order(ctx, data.OrderID.String())
+			data.Quarantined = nil
 			if err != nil {
 				data.Error = err.Error()
-			} else {
-				if data.Status != statusMap[or.Status] {
-					data.Status = statusMap[or.Status]
-					if or.Accrual != nil && !or.Accrual.IsZero() && data.Status == model.OrderStatusPROCESSED {
-						data.AddTransaction(model.ACCRUAL, *or.Accrual)
-					}
-				}
+				data.ErrorClass = classifyAccrualError(err)
+			} else if status, quarantined := checkAccrual(data, or, p.maxAccrual); quarantined != nil {
+				// the result is not applied, the order is checked again like after a failed check
+				data.Error = quarantineError(quarantined).Error()
+				data.ErrorClass = model.ErrorClassQuarantined
+				data.Quarantined = quarantined
+			} else if err = applyAccrual(data, status, or.Accrual); err != nil {
+				data.Error = err.Error()
+				data.ErrorClass = model.ErrorClassInvalidAmount
 			}
 
 			select {
@@ -146,6 +315,47 @@ func (p *TrackOrderProcessor) processOrder(ctx context.Context, inputCh <-chan *
 	return info
 }
 
-func NewTrackOrderProcessor(accrualCl accrual.AccrualClient) *TrackOrderProcessor {
-	return &TrackOrderProcessor{accrualCl: accrualCl}
+// applyAccrual moves the order to the status reported by the accrual service and credits a PROCESSED order.
+// Accruals are rounded to the points scale, the order keeps its status when the amount is out of range.
+func applyAccrual(order *model.Order, status model.OrderStatus, accrual *types.Decimal) error {
+	if order.Status == status {
+		return nil
+	}
+	var amount types.Points
+	if accrual != nil && status == model.OrderStatusPROCESSED {
+		var err error
+		if amount, err = types.RoundPoints(accrual.Decimal); err != nil {
+			return err
+		}
+	}
+	order.Status = status
+	if !amount.IsZero() {
+		order.AddTransaction(model.ACCRUAL, amount.Decimal())
+	}
+	return nil
+}
+
+func classifyAccrualError(err error) model.ErrorClass {
+	var status *accrual.StatusError
+	switch {
+	case errors.Is(err, accrual.ErrNoContent):
+		return model.ErrorClassNotRegistered
+	case errors.Is(err, accrual.ErrMalformedResponse):
+		return model.ErrorClassMalformed
+	case errors.As(err, &status) && status.Code >= http.StatusInternalServerError:
+		return model.ErrorClassServer
+	case errors.As(err, &status):
+		return model.ErrorClassClient
+	default:
+		return model.ErrorClassTransport
+	}
+}
+
+// NewTrackOrderProcessor creates a processor that quarantines accruals above maxAccrual, zero disables the check.
+func NewTrackOrderProcessor(providers *accrual.Providers, concurrency int, maxAccrual types.Decimal) *TrackOrderProcessor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &TrackOrderProcessor{providers: providers, concurrency: concurrency, maxAccrual: maxAccrual,
+		pausedUntil: make(map[string]time.Time)}
 }
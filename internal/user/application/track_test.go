@@ -0,0 +1,240 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func singleProvider(t *testing.T, client accrual.AccrualClient) *accrual.Providers {
+	providers, err := accrual.NewProviders(map[string]accrual.AccrualClient{model.DefaultProvider: client})
+	assert.NoError(t, err)
+	return providers
+}
+
+type countingAccrualClient struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *countingAccrualClient) Order(_ context.Context, number string) (*dto.Order, error) {
+	c.mu.Lock()
+	c.inFlight++
+	c.peak = max(c.peak, c.inFlight)
+	c.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return &dto.Order{Number: number, Status: dto.StatusPROCESSING}, nil
+}
+
+func TestTrackOrderProcessorShouldBoundConcurrency(t *testing.T) {
+	client := &countingAccrualClient{}
+	processor := NewTrackOrderProcessor(singleProvider(t, client), 3, types.Decimal{})
+	orders := make([]*model.Order, 20)
+	for i := range orders {
+		orders[i] = &model.Order{OrderID: model.OrderID{Value: int64(i + 1)}, Status: model.OrderStatusNEW}
+	}
+
+	processed := 0
+	for order := range processor.Process(context.Background(), orders) {
+		assert.Equal(t, model.OrderStatusPROCESSING, order.Status)
+		processed++
+	}
+
+	assert.Equal(t, len(orders), processed)
+	assert.LessOrEqual(t, client.peak, 3)
+	assert.Greater(t, client.peak, 0)
+}
+
+type throttlingAccrualClient struct {
+	until time.Time
+}
+
+func (c *throttlingAccrualClient) Order(_ context.Context, number string) (*dto.Order, error) {
+	if number != "1" {
+		return nil, &accrual.TooManyRequestsError{Until: c.until}
+	}
+	return &dto.Order{Number: number, Status: dto.StatusPROCESSING}, nil
+}
+
+func TestTrackOrderProcessorShouldLeaveThrottledOrdersUntouched(t *testing.T) {
+	processor := NewTrackOrderProcessor(singleProvider(t, &throttlingAccrualClient{until: time.Now().Add(time.Minute)}), 2, types.Decimal{})
+	orders := []*model.Order{
+		{OrderID: model.OrderID{Value: 1}, Status: model.OrderStatusNEW},
+		{OrderID: model.OrderID{Value: 2}, Status: model.OrderStatusNEW, Error: "previous"},
+		{OrderID: model.OrderID{Value: 3}, Status: model.OrderStatusNEW},
+	}
+
+	var processed []*model.Order
+	for order := range processor.Process(context.Background(), orders) {
+		processed = append(processed, order)
+	}
+
+	assert.Len(t, processed, 1)
+	assert.Equal(t, int64(1), processed[0].OrderID.Value)
+	assert.Equal(t, "previous", orders[1].Error)
+	assert.Equal(t, model.OrderStatusNEW, orders[2].Status)
+	assert.True(t, processor.Paused())
+}
+
+type scriptedAccrualClient struct {
+	errors map[string]error
+}
+
+func (c *scriptedAccrualClient) Order(_ context.Context, number string) (*dto.Order, error) {
+	if err, ok := c.errors[number]; ok {
+		return nil, err
+	}
+	return &dto.Order{Number: number, Status: dto.StatusPROCESSING}, nil
+}
+
+func TestTrackOrderHandlerShouldSaveLeasedOrdersAndReleaseSkipped(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	policy := model.TrackingPolicy{Processing: time.Minute, Lease: time.Minute}
+	client := &scriptedAccrualClient{errors: map[string]error{"2": accrual.ErrUnavailable}}
+	handler := NewTrackOrderHandler(mockUow, NewTrackOrderProcessor(singleProvider(t, client), 2, types.Decimal{}), NewTierEvaluator(), policy, "node-1")
+	orders := []*model.Order{
+		{OrderID: model.OrderID{Value: 1}, Status: model.OrderStatusNEW},
+		{OrderID: model.OrderID{Value: 2}, Status: model.OrderStatusNEW},
+		{OrderID: model.OrderID{Value: 3}, Status: model.OrderStatusNEW},
+	}
+
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockRepo.EXPECT().Claim(ctx, gomock.Any(), 10, model.OrderStatusNEW, model.OrderStatusPROCESSING).
+		DoAndReturn(func(_ context.Context, lease model.Lease, _ int, _ ...model.OrderStatus) ([]*model.Order, error) {
+			assert.Equal(t, "node-1", lease.Owner)
+			assert.Equal(t, time.Minute, lease.Until.Sub(lease.At))
+			return orders, nil
+		})
+	mockUow.EXPECT().BeginTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		}).Times(2)
+	mockRepo.EXPECT().UpdateLeased(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, order *model.Order, lease model.Lease) error {
+			assert.Equal(t, "node-1", lease.Owner)
+			assert.Equal(t, model.OrderStatusPROCESSING, order.Status)
+			if order.OrderID.Value == 3 {
+				return model.ErrLeaseLost
+			}
+			return nil
+		}).Times(2)
+	mockRepo.EXPECT().Release(gomock.Any(), []model.OrderID{{Value: 2}}, "node-1").Return(nil)
+
+	report, err := handler.Handle(ctx, &TrackOrderCommand{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Checked)
+	assert.Equal(t, 1, report.Released)
+	assert.Equal(t, map[string]int{"NEW->PROCESSING": 1}, report.Transitions)
+}
+
+func TestTrackOrderProcessorShouldRouteOrdersToTheirProvider(t *testing.T) {
+	brandA := &countingAccrualClient{}
+	providers, err := accrual.NewProviders(map[string]accrual.AccrualClient{
+		model.DefaultProvider: &throttlingAccrualClient{until: time.Now().Add(time.Minute)},
+		"brand-a":             brandA,
+	})
+	assert.NoError(t, err)
+	processor := NewTrackOrderProcessor(providers, 2, types.Decimal{})
+	orders := []*model.Order{
+		{OrderID: model.OrderID{Value: 2}, Status: model.OrderStatusNEW, Provider: "brand-a"},
+		{OrderID: model.OrderID{Value: 3}, Status: model.OrderStatusNEW, Provider: model.DefaultProvider},
+		{OrderID: model.OrderID{Value: 4}, Status: model.OrderStatusNEW, Provider: "retired"},
+	}
+
+	var processed []*model.Order
+	for order := range processor.Process(context.Background(), orders) {
+		processed = append(processed, order)
+	}
+
+	assert.Len(t, processed, 1)
+	assert.Equal(t, "brand-a", processed[0].Provider)
+	assert.Equal(t, 1, brandA.peak)
+	assert.False(t, processor.Paused())
+}
+
+func TestTrackOrderHandlerShouldIgnoreReplayedAccruals(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockTiers := mocks.NewMockTierRepository(ctrl)
+	policy := model.TrackingPolicy{Lease: time.Minute}
+	client := &staticAccrualClient{result: dto.Order{Number: "12345678903", Status: dto.StatusPROCESSED,
+		Accrual: accrualOf(t, "500")}}
+	handler := NewTrackOrderHandler(mockUow, NewTrackOrderProcessor(singleProvider(t, client), 1, types.Decimal{}),
+		NewTierEvaluator(), policy, "node-1")
+	orderID := model.OrderID{Value: 12345678903}
+	credited := &model.Order{OrderID: orderID, Status: model.OrderStatusPROCESSED, Provider: model.DefaultProvider,
+		Accrual: types.Decimal{Decimal: decimal.NewFromInt(500)}}
+
+	claimable := true
+	mockUow.EXPECT().OrderRepository().Return(mockRepo).AnyTimes()
+	mockUow.EXPECT().TierRepository().Return(mockTiers).AnyTimes()
+	mockUow.EXPECT().BeginTx(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		}).AnyTimes()
+	mockTiers.EXPECT().GetByUser(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows).AnyTimes()
+	mockTiers.EXPECT().RollingAccrued(gomock.Any(), gomock.Any(), gomock.Any()).Return(types.Decimal{}, nil)
+	mockTiers.EXPECT().GetAll(gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().Claim(ctx, gomock.Any(), 10, model.OrderStatusNEW, model.OrderStatusPROCESSING).
+		DoAndReturn(func(context.Context, model.Lease, int, ...model.OrderStatus) ([]*model.Order, error) {
+			if !claimable {
+				return nil, nil
+			}
+			// the same stale order comes back as long as it is not saved in a final status
+			return []*model.Order{{OrderID: orderID, Status: model.OrderStatusPROCESSING,
+				Provider: model.DefaultProvider}}, nil
+		}).Times(3)
+	gomock.InOrder(
+		// the result is saved, but the run is not acknowledged and the order stays claimable
+		mockRepo.EXPECT().UpdateLeased(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, order *model.Order, _ model.Lease) error {
+				assert.Len(t, order.Transactions(), 1)
+				return nil
+			}),
+		mockRepo.EXPECT().UpdateLeased(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.ErrAlreadyCredited),
+		mockRepo.EXPECT().Get(gomock.Any(), orderID).Return(credited, nil),
+		mockRepo.EXPECT().UpdateLeased(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, order *model.Order, _ model.Lease) error {
+				assert.Empty(t, order.Transactions(), "the order must not be credited again")
+				assert.Equal(t, credited.Accrual, order.Accrual)
+				claimable = !order.Status.Final()
+				return nil
+			}),
+	)
+
+	var checked, alreadyCredited int
+	for i := 0; i < 3; i++ {
+		report, err := handler.Handle(ctx, &TrackOrderCommand{Limit: 10})
+
+		assert.NoError(t, err)
+		checked += report.Checked
+		alreadyCredited += report.AlreadyCredited
+	}
+
+	assert.False(t, claimable, "the already credited order should leave the queue")
+	assert.Equal(t, 2, checked)
+	assert.Equal(t, 1, alreadyCredited)
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrTransactionNotFound = domain.NewResourceNotFound("transaction not found")
+	ErrOrderNotFound       = domain.NewResourceNotFound("order not found")
+)
+
+type transactionService struct {
+	uow uow.UnitOfWork
+}
+
+func (t *transactionService) Reverse(ctx context.Context, id int64, reason model.ReasonCode) (*model.Transaction, error) {
+	var result *model.Transaction
+	err := t.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		tr, err := uow.BonusMovementRepository().GetForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTransactionNotFound
+			}
+			return err
+		}
+		result, err = t.reverse(ctx, uow, tr, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t *transactionService) ReverseOrder(ctx context.Context, orderID model.OrderID, tt model.TransactionType, reason model.ReasonCode) (*model.Transaction, error) {
+	var result *model.Transaction
+	err := t.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		tr, err := uow.BonusMovementRepository().GetByOrderForUpdate(ctx, orderID, tt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+		result, err = t.reverse(ctx, uow, tr, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// reverse compensates the transaction, reversing an accrual also claws back the tier bonus granted on top of it.
+func (t *transactionService) reverse(ctx context.Context, uow uow.UnitOfWork, tr *model.Transaction, reason model.ReasonCode) (*model.Transaction, error) {
+	rep := uow.BonusMovementRepository()
+	reversed, err := rep.IsReversed(ctx, tr.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reversed {
+		return nil, model.ErrTransactionReversed
+	}
+	compensation, err := tr.Reverse(reason)
+	if err != nil {
+		return nil, err
+	}
+	compensations := []*model.Transaction{compensation}
+	if tr.Type == model.ACCRUAL {
+		bonus, err := t.bonusCompensation(ctx, rep, tr.OrderID, reason)
+		if err != nil {
+			return nil, err
+		}
+		if bonus != nil {
+			compensations = append(compensations, bonus)
+		}
+	}
+	if !compensation.Type.IsCredit() {
+		var total types.Decimal
+		for _, c := range compensations {
+			total = total.Add(c.Amount)
+		}
+		bal, err := uow.BonusBalanceRepository().Get(ctx, tr.UserID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		if bal == nil {
+			bal = &model.BonusBalance{}
+		}
+		if bal.Current.Cmp(total) < 0 {
+			return nil, ErrNegativeBalance
+		}
+	}
+	for _, c := range compensations {
+		if _, err = rep.Insert(ctx, c); err != nil {
+			return nil, err
+		}
+	}
+	return compensation, nil
+}
+
+// bonusCompensation returns nil when the order got no bonus or it is reversed already.
+func (t *transactionService) bonusCompensation(ctx context.Context, rep repository.TransactionRepository, orderID model.OrderID, reason model.ReasonCode) (*model.Transaction, error) {
+	bonus, err := rep.GetByOrderForUpdate(ctx, orderID, model.BONUS)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	reversed, err := rep.IsReversed(ctx, bonus.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reversed {
+		return nil, nil
+	}
+	return bonus.Reverse(reason)
+}
+
+func NewTransactionService(uow uow.UnitOfWork) domain.TransactionService {
+	return &transactionService{uow: uow}
+}
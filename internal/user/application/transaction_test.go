@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReverseOrderAccrualShouldReverseTierBonus(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockTransactionRepository(ctrl)
+	mockBalRepo := mocks.NewMockBonusBalanceRepository(ctrl)
+	orderID, _ := model.NewOrderID("12345678903")
+	accrual := &model.Transaction{ID: 1, UserID: 1, Type: model.ACCRUAL, OrderID: orderID,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(100)}}
+	bonus := &model.Transaction{ID: 2, UserID: 1, Type: model.BONUS, OrderID: orderID,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(10)}}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().BonusMovementRepository().Return(mockRepo).AnyTimes()
+
+	mockUow.EXPECT().BonusBalanceRepository().Return(mockBalRepo)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.ACCRUAL).Return(accrual, nil)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.BONUS).Return(bonus, nil)
+
+	mockRepo.EXPECT().IsReversed(ctx, int64(1)).Return(false, nil)
+
+	mockRepo.EXPECT().IsReversed(ctx, int64(2)).Return(false, nil)
+
+	mockBalRepo.EXPECT().Get(ctx, int64(1)).Return(&model.BonusBalance{
+		UserID:  1,
+		Current: types.Decimal{Decimal: decimal.NewFromInt(110)},
+	}, nil)
+
+	var inserted []*model.Transaction
+	mockRepo.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, tr *model.Transaction) (int64, error) {
+		inserted = append(inserted, tr)
+		return int64(len(inserted) + 2), nil
+	}).Times(2)
+
+	sut := NewTransactionService(mockUow)
+
+	result, err := sut.ReverseOrder(ctx, orderID, model.ACCRUAL, model.ReasonAccrualCorrected)
+
+	assert.NoError(t, err, "ReverseOrder should return no error")
+	assert.Equal(t, int64(1), *result.ReferenceID)
+	if assert.Len(t, inserted, 2) {
+		assert.Equal(t, model.REVERSAL, inserted[1].Type)
+		assert.Equal(t, int64(2), *inserted[1].ReferenceID)
+		assert.Equal(t, bonus.Amount, inserted[1].Amount)
+	}
+}
+
+func TestReverseOrderAccrualWithoutBalanceForBonusShouldReturnError(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockTransactionRepository(ctrl)
+	mockBalRepo := mocks.NewMockBonusBalanceRepository(ctrl)
+	orderID, _ := model.NewOrderID("12345678903")
+	accrual := &model.Transaction{ID: 1, UserID: 1, Type: model.ACCRUAL, OrderID: orderID,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(100)}}
+	bonus := &model.Transaction{ID: 2, UserID: 1, Type: model.BONUS, OrderID: orderID,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(10)}}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().BonusMovementRepository().Return(mockRepo).AnyTimes()
+
+	mockUow.EXPECT().BonusBalanceRepository().Return(mockBalRepo)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.ACCRUAL).Return(accrual, nil)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.BONUS).Return(bonus, nil)
+
+	mockRepo.EXPECT().IsReversed(ctx, int64(1)).Return(false, nil)
+
+	mockRepo.EXPECT().IsReversed(ctx, int64(2)).Return(false, nil)
+
+	mockBalRepo.EXPECT().Get(ctx, int64(1)).Return(&model.BonusBalance{
+		UserID:  1,
+		Current: types.Decimal{Decimal: decimal.NewFromInt(100)},
+	}, nil)
+
+	sut := NewTransactionService(mockUow)
+
+	_, err := sut.ReverseOrder(ctx, orderID, model.ACCRUAL, model.ReasonAccrualCorrected)
+
+	assert.ErrorIs(t, err, ErrNegativeBalance, "ReverseOrder should return error")
+}
+
+func TestReverseOrderAccrualWithoutBonusShouldReverseAccrualOnly(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockRepo := mocks.NewMockTransactionRepository(ctrl)
+	mockBalRepo := mocks.NewMockBonusBalanceRepository(ctrl)
+	orderID, _ := model.NewOrderID("12345678903")
+	accrual := &model.Transaction{ID: 1, UserID: 1, Type: model.ACCRUAL, OrderID: orderID,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(100)}}
+
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+
+	mockUow.EXPECT().BonusMovementRepository().Return(mockRepo).AnyTimes()
+
+	mockUow.EXPECT().BonusBalanceRepository().Return(mockBalRepo)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.ACCRUAL).Return(accrual, nil)
+
+	mockRepo.EXPECT().GetByOrderForUpdate(ctx, orderID, model.BONUS).Return(nil, pgx.ErrNoRows)
+
+	mockRepo.EXPECT().IsReversed(ctx, int64(1)).Return(false, nil)
+
+	mockBalRepo.EXPECT().Get(ctx, int64(1)).Return(&model.BonusBalance{
+		UserID:  1,
+		Current: types.Decimal{Decimal: decimal.NewFromInt(100)},
+	}, nil)
+
+	mockRepo.EXPECT().Insert(ctx, gomock.Any()).Return(int64(3), nil)
+
+	sut := NewTransactionService(mockUow)
+
+	result, err := sut.ReverseOrder(ctx, orderID, model.ACCRUAL, model.ReasonAccrualCorrected)
+
+	assert.NoError(t, err, "ReverseOrder should return no error")
+	assert.Equal(t, model.REVERSAL, result.Type)
+}
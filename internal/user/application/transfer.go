@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+var ErrRecipientNotFound = domain.NewResourceNotFound("recipient not found")
+
+type transferService struct {
+	uow    uow.UnitOfWork
+	limits model.TransferLimits
+}
+
+func (t *transferService) Transfer(ctx context.Context, login string, amount types.Decimal, note string) (*model.Transfer, error) {
+	senderID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, model.ErrTransferAmount
+	}
+	transfer := &model.Transfer{
+		SenderID: senderID,
+		Amount:   amount,
+		Note:     note,
+	}
+	err = t.uow.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		userRep := uow.UserRepository()
+		recipient, err := userRep.Get(ctx, login)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRecipientNotFound
+			}
+			return err
+		}
+		if recipient.ID == senderID {
+			return model.ErrTransferToSelf
+		}
+		transfer.RecipientID = recipient.ID
+		if err = userRep.Lock(ctx, senderID); err != nil {
+			return err
+		}
+		transferRep := uow.TransferRepository()
+		// limits are counted per UTC day
+		sent, count, err := transferRep.Sent(ctx, senderID, time.Now().UTC().Truncate(24*time.Hour))
+		if err != nil {
+			return err
+		}
+		if err = t.limits.Check(amount, sent, count); err != nil {
+			return err
+		}
+		bal, err := uow.BonusBalanceRepository().Get(ctx, senderID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if bal == nil {
+			bal = &model.BonusBalance{}
+		}
+		if bal.Current.Cmp(amount) < 0 {
+			return ErrNegativeBalance
+		}
+		if _, err = transferRep.Insert(ctx, transfer); err != nil {
+			return err
+		}
+		debit, credit := transfer.Entries()
+		trRep := uow.BonusMovementRepository()
+		if _, err = trRep.Insert(ctx, debit); err != nil {
+			return err
+		}
+		if _, err = trRep.Insert(ctx, credit); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func NewTransferService(uow uow.UnitOfWork, limits model.TransferLimits) domain.TransferService {
+	return &transferService{uow: uow, limits: limits}
+}
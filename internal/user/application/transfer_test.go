@@ -0,0 +1,162 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/DimKa163/gophermart/internal/user/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func expectTransferTx(ctx context.Context, mockUow *mocks.MockUnitOfWork) {
+	mockUow.EXPECT().BeginTx(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context, uow uow.UnitOfWork) error) error {
+			return fn(ctx, mockUow)
+		})
+}
+
+func TestTransferShouldInsertPairedEntries(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockTransfers := mocks.NewMockTransferRepository(ctrl)
+	mockBalRepo := mocks.NewMockBonusBalanceRepository(ctrl)
+	mockTransactions := mocks.NewMockTransactionRepository(ctrl)
+	amount := types.Decimal{Decimal: decimal.NewFromInt(100)}
+
+	expectTransferTx(ctx, mockUow)
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+	mockUow.EXPECT().TransferRepository().Return(mockTransfers)
+	mockUow.EXPECT().BonusBalanceRepository().Return(mockBalRepo)
+	mockUow.EXPECT().BonusMovementRepository().Return(mockTransactions)
+
+	mockURepo.EXPECT().Get(ctx, "bob").Return(&model.User{ID: 2, Login: "bob"}, nil)
+	mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil)
+	mockTransfers.EXPECT().Sent(ctx, int64(1), gomock.Any()).Return(types.Decimal{}, 0, nil)
+	mockBalRepo.EXPECT().Get(ctx, int64(1)).Return(&model.BonusBalance{UserID: 1, Current: amount}, nil)
+	mockTransfers.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, transfer *model.Transfer) (int64, error) {
+			transfer.ID = 9
+			return transfer.ID, nil
+		})
+	var entries []*model.Transaction
+	mockTransactions.EXPECT().Insert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tr *model.Transaction) (int64, error) {
+			entries = append(entries, tr)
+			return int64(len(entries)), nil
+		}).Times(2)
+
+	sut := NewTransferService(mockUow, model.TransferLimits{})
+
+	result, err := sut.Transfer(ctx, "bob", amount, "thanks")
+
+	assert.NoError(t, err, "Transfer should return no error")
+	assert.Equal(t, int64(2), result.RecipientID, "recipient should be resolved by login")
+	if assert.Len(t, entries, 2, "a debit and a credit should be posted") {
+		assert.Equal(t, model.TRANSFER_OUT, entries[0].Type)
+		assert.Equal(t, int64(1), entries[0].UserID)
+		assert.Equal(t, model.TRANSFER_IN, entries[1].Type)
+		assert.Equal(t, int64(2), entries[1].UserID)
+		for _, entry := range entries {
+			assert.Equal(t, int64(9), *entry.TransferID, "entries should reference the transfer")
+			assert.Equal(t, amount, entry.Amount)
+		}
+	}
+}
+
+func TestTransferToSelfShouldFail(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+
+	expectTransferTx(ctx, mockUow)
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockURepo.EXPECT().Get(ctx, "alice").Return(&model.User{ID: 1, Login: "alice"}, nil)
+
+	sut := NewTransferService(mockUow, model.TransferLimits{})
+
+	_, err := sut.Transfer(ctx, "alice", types.Decimal{Decimal: decimal.NewFromInt(100)}, "")
+
+	assert.ErrorIs(t, err, model.ErrTransferToSelf, "Transfer should reject transfers to yourself")
+}
+
+func TestTransferToUnknownRecipientShouldReturnNotFound(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+
+	expectTransferTx(ctx, mockUow)
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+
+	mockURepo.EXPECT().Get(ctx, "nobody").Return(nil, pgx.ErrNoRows)
+
+	sut := NewTransferService(mockUow, model.TransferLimits{})
+
+	_, err := sut.Transfer(ctx, "nobody", types.Decimal{Decimal: decimal.NewFromInt(100)}, "")
+
+	assert.ErrorIs(t, err, ErrRecipientNotFound, "Transfer should return not found")
+}
+
+func TestTransferOverDailyLimitShouldFail(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockTransfers := mocks.NewMockTransferRepository(ctrl)
+
+	expectTransferTx(ctx, mockUow)
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+	mockUow.EXPECT().TransferRepository().Return(mockTransfers)
+
+	mockURepo.EXPECT().Get(ctx, "bob").Return(&model.User{ID: 2, Login: "bob"}, nil)
+	mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil)
+	mockTransfers.EXPECT().Sent(ctx, int64(1), gomock.Any()).
+		Return(types.Decimal{Decimal: decimal.NewFromInt(450)}, 3, nil)
+
+	sut := NewTransferService(mockUow, model.TransferLimits{DailyAmount: types.Decimal{Decimal: decimal.NewFromInt(500)}})
+
+	_, err := sut.Transfer(ctx, "bob", types.Decimal{Decimal: decimal.NewFromInt(100)}, "")
+
+	assert.ErrorIs(t, err, model.ErrTransferDailyLimit, "Transfer should respect the daily limit")
+}
+
+func TestTransferWithoutBalanceShouldReturnError(t *testing.T) {
+	ctx := auth.SetUser(context.Background(), 1)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockUow := mocks.NewMockUnitOfWork(ctrl)
+	mockURepo := mocks.NewMockUserRepository(ctrl)
+	mockTransfers := mocks.NewMockTransferRepository(ctrl)
+	mockBalRepo := mocks.NewMockBonusBalanceRepository(ctrl)
+
+	expectTransferTx(ctx, mockUow)
+	mockUow.EXPECT().UserRepository().Return(mockURepo)
+	mockUow.EXPECT().TransferRepository().Return(mockTransfers)
+	mockUow.EXPECT().BonusBalanceRepository().Return(mockBalRepo)
+
+	mockURepo.EXPECT().Get(ctx, "bob").Return(&model.User{ID: 2, Login: "bob"}, nil)
+	mockURepo.EXPECT().Lock(ctx, int64(1)).Return(nil)
+	mockTransfers.EXPECT().Sent(ctx, int64(1), gomock.Any()).Return(types.Decimal{}, 0, nil)
+	mockBalRepo.EXPECT().Get(ctx, int64(1)).Return(&model.BonusBalance{UserID: 1,
+		Current: types.Decimal{Decimal: decimal.NewFromInt(50)}}, nil)
+
+	sut := NewTransferService(mockUow, model.TransferLimits{})
+
+	_, err := sut.Transfer(ctx, "bob", types.Decimal{Decimal: decimal.NewFromInt(100)}, "")
+
+	assert.ErrorIs(t, err, ErrNegativeBalance, "Transfer should return error")
+}
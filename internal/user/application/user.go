@@ -8,6 +8,7 @@ import (
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/uow"
 	"github.com/jackc/pgx/v5"
+	"time"
 )
 
 var (
@@ -79,6 +80,14 @@ func (u *userService) Balance(ctx context.Context) (*model.BonusBalance, error)
 	return bal, nil
 }
 
+func (u *userService) BalanceAt(ctx context.Context, at time.Time) (*model.BonusBalance, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.uow.BonusBalanceRepository().GetAt(ctx, userID, at)
+}
+
 func (u *userService) Withdrawal(ctx context.Context) ([]*model.Transaction, error) {
 	userID, err := auth.User(ctx)
 	if err != nil {
@@ -93,6 +102,56 @@ func (u *userService) Withdrawal(ctx context.Context) ([]*model.Transaction, err
 	return items, nil
 }
 
+func (u *userService) Transactions(ctx context.Context) ([]*model.Transaction, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := u.uow.BonusMovementRepository().GetAll(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (u *userService) Statement(ctx context.Context, from, to time.Time, cursor *model.StatementCursor, limit int) (*model.Statement, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, model.ErrInvalidPeriod
+	}
+	start := model.StatementCursor{CreatedAt: from}
+	if cursor != nil {
+		if cursor.CreatedAt.Before(from) || !cursor.CreatedAt.Before(to) {
+			return nil, model.ErrInvalidCursor
+		}
+		start = *cursor
+	}
+	rep := u.uow.BonusMovementRepository()
+	opening, err := rep.BalanceBefore(ctx, userID, model.StatementCursor{CreatedAt: from})
+	if err != nil {
+		return nil, err
+	}
+	balance := opening
+	if cursor != nil {
+		if balance, err = rep.BalanceBefore(ctx, userID, model.StatementCursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID + 1}); err != nil {
+			return nil, err
+		}
+	}
+	totals, err := rep.Totals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// one extra row tells whether there is a next page
+	page, err := rep.GetPage(ctx, userID, start, to, nil, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewStatement(from, to, opening, balance, totals, page, limit), nil
+}
+
 func (u *userService) authenticate(user *model.User, password string) (string, error) {
 	token, err := u.auth.Authenticate(user.ID, []byte(password), user.Password, user.Salt)
 	if err != nil {
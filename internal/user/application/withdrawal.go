@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"time"
+)
+
+var (
+	ErrWithdrawalRuleNotFound = domain.NewResourceNotFound("withdrawal rule not found")
+	ErrWithdrawalRuleScope    = domain.NewProblemError("withdrawal rule must target either a user or a tier", nil)
+)
+
+type withdrawalPolicy struct {
+	uow   uow.UnitOfWork
+	rules model.WithdrawalRules
+}
+
+func (w *withdrawalPolicy) Check(ctx context.Context, userID int64, sum types.Decimal, orderTotal *types.Decimal) error {
+	rules, err := w.effective(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// caps are counted per UTC day and month
+	now := time.Now().UTC()
+	dayStart := now.Truncate(24 * time.Hour)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	usage, err := w.uow.WithdrawalRuleRepository().Usage(ctx, userID, dayStart, monthStart)
+	if err != nil {
+		return err
+	}
+	return rules.Check(sum, orderTotal, usage, now)
+}
+
+func (w *withdrawalPolicy) Rules(ctx context.Context) (model.WithdrawalRules, error) {
+	userID, err := auth.User(ctx)
+	if err != nil {
+		return model.WithdrawalRules{}, err
+	}
+	return w.effective(ctx, userID)
+}
+
+// effective applies the tier override and then the user override on top of the global rules.
+func (w *withdrawalPolicy) effective(ctx context.Context, userID int64) (model.WithdrawalRules, error) {
+	overrides, err := w.uow.WithdrawalRuleRepository().GetForUser(ctx, userID)
+	if err != nil {
+		return model.WithdrawalRules{}, err
+	}
+	rules := w.rules
+	for _, override := range overrides {
+		rules = rules.Apply(override)
+	}
+	return rules, nil
+}
+
+func (w *withdrawalPolicy) Overrides(ctx context.Context) ([]*model.WithdrawalRuleOverride, error) {
+	return w.uow.WithdrawalRuleRepository().GetAll(ctx)
+}
+
+func (w *withdrawalPolicy) SaveOverride(ctx context.Context, override *model.WithdrawalRuleOverride) (*model.WithdrawalRuleOverride, error) {
+	if (override.UserID == nil) == (override.TierID == nil) {
+		return nil, ErrWithdrawalRuleScope
+	}
+	if _, err := w.uow.WithdrawalRuleRepository().Save(ctx, override); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+func (w *withdrawalPolicy) DeleteOverride(ctx context.Context, id int64) error {
+	deleted, err := w.uow.WithdrawalRuleRepository().Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWithdrawalRuleNotFound
+	}
+	return nil
+}
+
+func NewWithdrawalPolicy(uow uow.UnitOfWork, rules model.WithdrawalRules) domain.WithdrawalPolicy {
+	return &withdrawalPolicy{uow: uow, rules: rules}
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type BalanceService interface {
+	// At returns the balance of any user as of the given moment.
+	At(ctx context.Context, userID int64, at time.Time) (*model.BonusBalance, error)
+
+	// Snapshot records balances up to cutoff so that point-in-time queries only sum recent movements.
+	Snapshot(ctx context.Context, cutoff time.Time) (int64, error)
+}
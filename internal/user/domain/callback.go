@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type AccrualCallbackService interface {
+	// Apply saves an accrual result pushed by the accrual service, status is one of the accrual service statuses.
+	Apply(ctx context.Context, id model.OrderID, status string, accrual *types.Decimal) (*model.Order, error)
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type DeadLetterService interface {
+	List(ctx context.Context, limit, offset int) ([]*model.Order, error)
+
+	Get(ctx context.Context, id model.OrderID) (*model.Order, error)
+
+	// Requeue hands a dead-lettered order back to order tracking.
+	Requeue(ctx context.Context, id model.OrderID) (*model.Order, error)
+
+	// Resolve settles a dead-lettered order by hand as PROCESSED with the given accrual or as INVALID.
+	Resolve(ctx context.Context, id model.OrderID, status model.OrderStatus, accrual types.Decimal) (*model.Order, error)
+}
@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ExpirationService interface {
+	Upcoming(ctx context.Context) ([]*model.ExpiringPoints, error)
+
+	Expire(ctx context.Context, at time.Time, dryRun bool) (*model.ExpirationReport, error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ExportService interface {
+	Create(ctx context.Context, format model.ExportFormat, from, to time.Time, tt []model.TransactionType) (*model.Export, error)
+
+	Get(ctx context.Context, id int64) (*model.Export, error)
+
+	// Download returns the export with the path of its file while the download link is valid.
+	Download(ctx context.Context, id int64) (*model.Export, string, error)
+
+	// Process generates pending exports and returns how many were completed.
+	Process(ctx context.Context) (int, error)
+
+	// Cleanup removes files of exports expired at the given moment.
+	Cleanup(ctx context.Context, at time.Time) (int, error)
+}
+
+// ExportWriter encodes movements into an export file.
+type ExportWriter interface {
+	Write(tr *model.Transaction) error
+
+	Close() error
+}
+
+// ExportStorage keeps generated export files.
+type ExportStorage interface {
+	Create(export *model.Export) (string, ExportWriter, error)
+
+	Path(file string) string
+
+	Remove(file string) error
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+func NewExportFormat(value string) (ExportFormat, error) {
+	switch format := ExportFormat(value); format {
+	case ExportFormatCSV, ExportFormatNDJSON:
+		return format, nil
+	}
+	return "", ErrExportFormat
+}
+
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+type ExportStatus int
+
+const (
+	ExportStatusNEW ExportStatus = iota
+	ExportStatusPROCESSING
+	ExportStatusDONE
+	ExportStatusFAILED
+	ExportStatusEXPIRED
+)
+
+func (s ExportStatus) String() string {
+	return [...]string{"NEW", "PROCESSING", "DONE", "FAILED", "EXPIRED"}[s]
+}
+
+var (
+	ErrExportFormat   = errors.New("invalid export format")
+	ErrExportNotReady = errors.New("export is not ready")
+	ErrExportExpired  = errors.New("export download link expired")
+)
+
+// Export is a request to write the movements of a period into a file that can be downloaded until ExpiresAt.
+type Export struct {
+	ID          int64
+	CreatedAt   time.Time
+	UserID      int64
+	Format      ExportFormat
+	From        time.Time
+	To          time.Time
+	Types       []TransactionType
+	Status      ExportStatus
+	Total       int
+	Done        int
+	File        string
+	Error       string
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+// Includes reports whether movements of the type go into the export, no types means every type.
+func (e *Export) Includes(tt TransactionType) bool {
+	if len(e.Types) == 0 {
+		return true
+	}
+	for _, t := range e.Types {
+		if t == tt {
+			return true
+		}
+	}
+	return false
+}
+
+// Progress returns the share of the exported movements in percent.
+func (e *Export) Progress() int {
+	if e.Status == ExportStatusDONE || e.Status == ExportStatusEXPIRED {
+		return 100
+	}
+	if e.Total == 0 {
+		return 0
+	}
+	return e.Done * 100 / e.Total
+}
+
+// Downloadable checks that the file is generated and the link is still valid at the given moment.
+func (e *Export) Downloadable(at time.Time) error {
+	if e.Status == ExportStatusEXPIRED || (e.ExpiresAt != nil && !at.Before(*e.ExpiresAt)) {
+		return ErrExportExpired
+	}
+	if e.Status != ExportStatusDONE {
+		return ErrExportNotReady
+	}
+	return nil
+}
+
+func (e *Export) Complete(file string, at time.Time, ttl time.Duration) {
+	expiresAt := at.Add(ttl)
+	e.Status = ExportStatusDONE
+	e.File = file
+	e.Done = e.Total
+	e.CompletedAt = &at
+	e.ExpiresAt = &expiresAt
+}
+
+func (e *Export) Fail(err error, at time.Time) {
+	e.Status = ExportStatusFAILED
+	e.Error = err.Error()
+	e.CompletedAt = &at
+}
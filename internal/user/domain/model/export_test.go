@@ -0,0 +1,79 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestExportDownloadable(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	done := func(ttl time.Duration) Export {
+		export := Export{Status: ExportStatusPROCESSING}
+		export.Complete("export.csv", now.Add(-time.Hour), ttl)
+		return export
+	}
+	cases := []struct {
+		name        string
+		export      Export
+		expectedErr error
+	}{
+		{
+			name:        "new",
+			export:      Export{Status: ExportStatusNEW},
+			expectedErr: ErrExportNotReady,
+		},
+		{
+			name:        "failed",
+			export:      Export{Status: ExportStatusFAILED},
+			expectedErr: ErrExportNotReady,
+		},
+		{
+			name:   "done",
+			export: done(2 * time.Hour),
+		},
+		{
+			name:        "link expired",
+			export:      done(time.Hour),
+			expectedErr: ErrExportExpired,
+		},
+		{
+			name:        "file removed",
+			export:      Export{Status: ExportStatusEXPIRED},
+			expectedErr: ErrExportExpired,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.export.Downloadable(now)
+			assert.Equal(t, c.expectedErr, err)
+		})
+	}
+}
+
+func TestExportProgress(t *testing.T) {
+	export := Export{Status: ExportStatusPROCESSING, Total: 400, Done: 100}
+	assert.Equal(t, 25, export.Progress())
+
+	export.Complete("export.ndjson", time.Now(), time.Hour)
+	assert.Equal(t, 100, export.Progress())
+	assert.Equal(t, 400, export.Done)
+}
+
+func TestExportIncludes(t *testing.T) {
+	all := Export{}
+	assert.True(t, all.Includes(PROMO), "no types should include every type")
+
+	withdrawals := Export{Types: []TransactionType{WITHDRAWAL, REFUND}}
+	assert.True(t, withdrawals.Includes(REFUND))
+	assert.False(t, withdrawals.Includes(ACCRUAL))
+}
+
+func TestParseTransactionType(t *testing.T) {
+	tt, err := ParseTransactionType("TRANSFER_IN")
+	assert.NoError(t, err)
+	assert.Equal(t, TRANSFER_IN, tt)
+
+	_, err = ParseTransactionType("transfer_in")
+	assert.Equal(t, ErrTransactionType, err)
+}
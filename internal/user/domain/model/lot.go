@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+// ErrLotsExhausted is returned when the open lots of the user do not cover a debit.
+var ErrLotsExhausted = errors.New("lots do not cover the debit")
+
+// Lot is a portion of points credited by a single transaction. Debits consume
+// lots FIFO, whatever remains in a lot expires together.
+type Lot struct {
+	ID            int64
+	UserID        int64
+	TransactionID int64
+	OrderID       OrderID
+	CreatedAt     time.Time
+	Amount        types.Decimal
+	Remaining     types.Decimal
+}
+
+type ExpirationPolicy struct {
+	Months int
+	Notice time.Duration
+}
+
+func (p ExpirationPolicy) Enabled() bool {
+	return p.Months > 0
+}
+
+// Cutoff returns the creation time up to which lots are expired at the given moment.
+func (p ExpirationPolicy) Cutoff(at time.Time) time.Time {
+	return at.AddDate(0, -p.Months, 0)
+}
+
+func (p ExpirationPolicy) ExpiresAt(createdAt time.Time) time.Time {
+	return createdAt.AddDate(0, p.Months, 0)
+}
+
+type ExpiringPoints struct {
+	Amount    types.Decimal
+	ExpiresAt time.Time
+}
+
+type ExpirationItem struct {
+	LotID     int64
+	UserID    int64
+	Amount    types.Decimal
+	ExpiresAt time.Time
+}
+
+type ExpirationReport struct {
+	At     time.Time
+	DryRun bool
+	Total  types.Decimal
+	Items  []*ExpirationItem
+}
+
+func (r *ExpirationReport) Add(lot *Lot, expiresAt time.Time) {
+	r.Items = append(r.Items, &ExpirationItem{
+		LotID:     lot.ID,
+		UserID:    lot.UserID,
+		Amount:    lot.Remaining,
+		ExpiresAt: expiresAt,
+	})
+	r.Total = r.Total.Add(lot.Remaining)
+}
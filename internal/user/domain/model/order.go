@@ -23,6 +23,11 @@ func (s OrderStatus) String() string {
 	return [...]string{"NEW", "PROCESSING", "INVALID", "PROCESSED"}[s]
 }
 
+// Final reports whether the accrual service is done with the order.
+func (s OrderStatus) Final() bool {
+	return s == OrderStatusINVALID || s == OrderStatusPROCESSED
+}
+
 type Order struct {
 	OrderID      OrderID
 	UploadedAt   *time.Time
@@ -32,6 +37,15 @@ type Order struct {
 	Accrual      types.Decimal
 	transactions []*Transaction
 	Error        string
+	ErrorClass   ErrorClass
+	NextCheckAt  time.Time
+	Attempts     int
+	// Provider is the accrual service checking the order.
+	Provider string
+	// DeadLetteredAt is set once the order failed too often and is no longer tracked automatically.
+	DeadLetteredAt *time.Time
+	// Quarantined is the result of the last check held back for review, it is saved together with the order.
+	Quarantined *QuarantinedResult
 }
 
 func (o *Order) AddTransaction(tt TransactionType, amount types.Decimal) {
@@ -56,6 +70,16 @@ func (o *Order) Transactions() []*Transaction {
 	return o.transactions
 }
 
+// Transaction returns the pending transaction of the given type if the order has one.
+func (o *Order) Transaction(tt TransactionType) *Transaction {
+	for _, tr := range o.transactions {
+		if tr.Type == tt {
+			return tr
+		}
+	}
+	return nil
+}
+
 type OrderIDError struct {
 	Message string
 }
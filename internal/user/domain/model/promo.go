@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"strings"
+	"time"
+)
+
+// giftCodeAlphabet leaves out characters that are easy to confuse when typed by hand.
+const giftCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const giftCodeLength = 12
+
+var (
+	ErrPromoCodeInvalid         = errors.New("promo code is invalid")
+	ErrPromoCodeNotActive       = errors.New("promo code is not active")
+	ErrPromoCodeExhausted       = errors.New("promo code has no redemptions left")
+	ErrPromoCodeAlreadyRedeemed = errors.New("promo code already redeemed")
+)
+
+type PromoCode struct {
+	ID        int64
+	CreatedAt time.Time
+	Code      string
+	Amount    types.Decimal
+	// MaxRedemptions caps redemptions across all users, zero means unlimited.
+	MaxRedemptions int
+	// PerUserLimit caps redemptions by a single user, zero means unlimited.
+	PerUserLimit int
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	// SingleUse marks generated gift codes.
+	SingleUse   bool
+	Redemptions int
+}
+
+// NormalizePromoCode makes codes case and whitespace insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the code definition before it is stored.
+func (p *PromoCode) Validate() error {
+	if p.Code == "" || len(p.Code) > 32 || !p.Amount.IsPositive() ||
+		p.MaxRedemptions < 0 || p.PerUserLimit < 0 {
+		return ErrPromoCodeInvalid
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return ErrPromoCodeInvalid
+	}
+	return nil
+}
+
+// CanRedeem checks the validity window and redemption limits for a user who already redeemed the code userRedemptions times.
+func (p *PromoCode) CanRedeem(at time.Time, userRedemptions int) error {
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return ErrPromoCodeNotActive
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return ErrPromoCodeNotActive
+	}
+	if p.MaxRedemptions > 0 && p.Redemptions >= p.MaxRedemptions {
+		return ErrPromoCodeExhausted
+	}
+	if p.PerUserLimit > 0 && userRedemptions >= p.PerUserLimit {
+		return ErrPromoCodeAlreadyRedeemed
+	}
+	return nil
+}
+
+// Credit builds the movement that grants the code amount to the user.
+func (p *PromoCode) Credit(userID int64) *Transaction {
+	return &Transaction{
+		UserID: userID,
+		Type:   PROMO,
+		Amount: p.Amount,
+		Note:   p.Code,
+	}
+}
+
+// NewGiftCode generates a single use code from the template, prefix is prepended to the random part.
+func NewGiftCode(template *PromoCode, prefix string) (*PromoCode, error) {
+	buf := make([]byte, giftCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	for i, b := range buf {
+		buf[i] = giftCodeAlphabet[int(b)%len(giftCodeAlphabet)]
+	}
+	return &PromoCode{
+		Code:           NormalizePromoCode(prefix) + string(buf),
+		Amount:         template.Amount,
+		MaxRedemptions: 1,
+		PerUserLimit:   1,
+		StartsAt:       template.StartsAt,
+		EndsAt:         template.EndsAt,
+		SingleUse:      true,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromoCodeCanRedeem(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	cases := []struct {
+		name            string
+		promo           PromoCode
+		userRedemptions int
+		expectedErr     error
+	}{
+		{
+			name:  "unlimited",
+			promo: PromoCode{},
+		},
+		{
+			name:        "not started",
+			promo:       PromoCode{StartsAt: &future},
+			expectedErr: ErrPromoCodeNotActive,
+		},
+		{
+			name:        "ended",
+			promo:       PromoCode{EndsAt: &past},
+			expectedErr: ErrPromoCodeNotActive,
+		},
+		{
+			name:  "within window",
+			promo: PromoCode{StartsAt: &past, EndsAt: &future},
+		},
+		{
+			name:        "exhausted",
+			promo:       PromoCode{MaxRedemptions: 10, Redemptions: 10},
+			expectedErr: ErrPromoCodeExhausted,
+		},
+		{
+			name:            "per user limit reached",
+			promo:           PromoCode{PerUserLimit: 2},
+			userRedemptions: 2,
+			expectedErr:     ErrPromoCodeAlreadyRedeemed,
+		},
+		{
+			name:            "per user limit not reached",
+			promo:           PromoCode{MaxRedemptions: 10, Redemptions: 9, PerUserLimit: 2},
+			userRedemptions: 1,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.promo.CanRedeem(now, c.userRedemptions)
+			assert.Equal(t, c.expectedErr, err)
+		})
+	}
+}
+
+func TestNewGiftCode(t *testing.T) {
+	template := &PromoCode{Amount: points(250)}
+
+	promo, err := NewGiftCode(template, " gift-")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(promo.Code, "GIFT-"), "prefix should be normalized")
+	assert.Len(t, promo.Code, len("GIFT-")+giftCodeLength)
+	assert.True(t, promo.SingleUse)
+	assert.Equal(t, 1, promo.MaxRedemptions)
+	assert.NoError(t, promo.Validate())
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultProvider names the accrual service configured by its plain address, it checks every order
+// no rule sends elsewhere, including orders uploaded before providers were introduced.
+const DefaultProvider = "default"
+
+var ErrUnknownProvider = errors.New("unknown accrual provider")
+
+// ProviderRoute sends orders whose number starts with Prefix to Provider.
+type ProviderRoute struct {
+	Prefix   string
+	Provider string
+}
+
+// ProviderRouting picks the accrual provider that checks an order.
+type ProviderRouting struct {
+	// Providers are the configured providers besides DefaultProvider.
+	Providers []string
+	Routes    []ProviderRoute
+}
+
+// Route returns the requested provider, otherwise the provider of the longest matching prefix or DefaultProvider.
+func (r ProviderRouting) Route(id OrderID, requested string) (string, error) {
+	if requested != "" {
+		if !r.Known(requested) {
+			return "", ErrUnknownProvider
+		}
+		return requested, nil
+	}
+	number := id.String()
+	provider, matched := DefaultProvider, 0
+	for _, route := range r.Routes {
+		if len(route.Prefix) > matched && strings.HasPrefix(number, route.Prefix) {
+			provider, matched = route.Provider, len(route.Prefix)
+		}
+	}
+	return provider, nil
+}
+
+func (r ProviderRouting) Known(provider string) bool {
+	if provider == DefaultProvider {
+		return true
+	}
+	for _, name := range r.Providers {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProviderRoutingRoute(t *testing.T) {
+	routing := ProviderRouting{
+		Providers: []string{"brand-a", "brand-b"},
+		Routes: []ProviderRoute{
+			{Prefix: "12", Provider: "brand-a"},
+			{Prefix: "1234", Provider: "brand-b"},
+		},
+	}
+	cases := []struct {
+		name             string
+		number           int64
+		requested        string
+		expectedProvider string
+		expectedErr      error
+	}{
+		{
+			name:             "prefix",
+			number:           12005,
+			expectedProvider: "brand-a",
+		},
+		{
+			name:             "longest prefix",
+			number:           12345678903,
+			expectedProvider: "brand-b",
+		},
+		{
+			name:             "no rule",
+			number:           79927398713,
+			expectedProvider: DefaultProvider,
+		},
+		{
+			name:             "requested",
+			number:           12345678903,
+			requested:        DefaultProvider,
+			expectedProvider: DefaultProvider,
+		},
+		{
+			name:        "unknown",
+			number:      12345678903,
+			requested:   "brand-c",
+			expectedErr: ErrUnknownProvider,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			provider, err := routing.Route(OrderID{Value: c.number}, c.requested)
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Equal(t, c.expectedProvider, provider)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+var ErrQuarantineReviewed = errors.New("quarantined accrual result is already reviewed")
+
+// QuarantineReason tells why an accrual result was not trusted.
+type QuarantineReason string
+
+const (
+	// QuarantineNumberMismatch is a result for another order than the one requested.
+	QuarantineNumberMismatch QuarantineReason = "NUMBER_MISMATCH"
+	QuarantineUnknownStatus  QuarantineReason = "UNKNOWN_STATUS"
+	// QuarantineAccrualRange is a negative accrual or one above the configured maximum.
+	QuarantineAccrualRange QuarantineReason = "ACCRUAL_OUT_OF_RANGE"
+	// QuarantineUnexpectedAccrual is an accrual reported for an order that is not PROCESSED.
+	QuarantineUnexpectedAccrual QuarantineReason = "UNEXPECTED_ACCRUAL"
+)
+
+// QuarantinedResult is an accrual result kept for review instead of being applied to the order.
+type QuarantinedResult struct {
+	ID        int64
+	CreatedAt time.Time
+	OrderID   OrderID
+	Provider  string
+	Reason    QuarantineReason
+	// Number, Status and Accrual are the result as the accrual service reported it.
+	Number     string
+	Status     string
+	Accrual    *types.Decimal
+	ReviewedAt *time.Time
+	Note       string
+}
+
+// Review marks the result as looked at by an admin, it is never applied to the order.
+func (q *QuarantinedResult) Review(note string, now time.Time) error {
+	if q.ReviewedAt != nil {
+		return ErrQuarantineReviewed
+	}
+	q.ReviewedAt = &now
+	q.Note = note
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+var (
+	ErrInvalidCursor = errors.New("statement cursor is invalid")
+	ErrInvalidPeriod = errors.New("statement period is invalid")
+)
+
+// StatementCursor points at the last movement of a statement page, the next page starts right after it.
+type StatementCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+func (c StatementCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)))
+}
+
+func ParseStatementCursor(value string) (*StatementCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var nanos, id int64
+	if _, err = fmt.Sscanf(string(data), "%d:%d", &nanos, &id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &StatementCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+type StatementEntry struct {
+	*Transaction
+	Balance types.Decimal
+}
+
+type StatementTotal struct {
+	Type   TransactionType
+	Amount types.Decimal
+	Count  int
+}
+
+// Statement is a page of movements for the period, opening, closing balances and totals always cover the whole period.
+type Statement struct {
+	From    time.Time
+	To      time.Time
+	Opening types.Decimal
+	Closing types.Decimal
+	Totals  []*StatementTotal
+	Entries []*StatementEntry
+	Next    *StatementCursor
+}
+
+// Signed returns the amount with the sign of its effect on the current balance.
+func (t *Transaction) Signed() types.Decimal {
+	if t.Type.IsCredit() {
+		return t.Amount
+	}
+	return types.Decimal{Decimal: t.Amount.Neg()}
+}
+
+// NewStatement computes running balances of the page starting from balance and the closing balance from the totals.
+func NewStatement(from, to time.Time, opening, balance types.Decimal, totals []*StatementTotal, page []*Transaction, limit int) *Statement {
+	statement := &Statement{
+		From:    from,
+		To:      to,
+		Opening: opening,
+		Closing: opening,
+		Totals:  totals,
+	}
+	for _, total := range totals {
+		tr := Transaction{Type: total.Type, Amount: total.Amount}
+		statement.Closing = statement.Closing.Add(tr.Signed())
+	}
+	if len(page) > limit {
+		page = page[:limit]
+		last := page[len(page)-1]
+		statement.Next = &StatementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	statement.Entries = make([]*StatementEntry, len(page))
+	for i, tr := range page {
+		balance = balance.Add(tr.Signed())
+		statement.Entries[i] = &StatementEntry{Transaction: tr, Balance: balance}
+	}
+	return statement
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewStatement(t *testing.T) {
+	from := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	page := []*Transaction{
+		{ID: 1, CreatedAt: from.Add(time.Hour), Type: ACCRUAL, Amount: points(500)},
+		{ID: 2, CreatedAt: from.Add(2 * time.Hour), Type: WITHDRAWAL, Amount: points(200)},
+		{ID: 3, CreatedAt: from.Add(3 * time.Hour), Type: PROMO, Amount: points(50)},
+	}
+	totals := []*StatementTotal{
+		{Type: ACCRUAL, Amount: points(500), Count: 1},
+		{Type: WITHDRAWAL, Amount: points(200), Count: 1},
+		{Type: PROMO, Amount: points(50), Count: 1},
+	}
+
+	statement := NewStatement(from, to, points(100), points(100), totals, page, 2)
+
+	assert.Equal(t, "450", statement.Closing.String(), "closing should include every movement of the period")
+	assert.Len(t, statement.Entries, 2, "page should be cut to the limit")
+	assert.Equal(t, "600", statement.Entries[0].Balance.String())
+	assert.Equal(t, "400", statement.Entries[1].Balance.String())
+	assert.Equal(t, &StatementCursor{CreatedAt: page[1].CreatedAt, ID: 2}, statement.Next)
+}
+
+func TestNewStatementLastPageShouldHaveNoCursor(t *testing.T) {
+	from := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	page := []*Transaction{
+		{ID: 1, CreatedAt: from, Type: EXPIRATION, Amount: points(30)},
+	}
+
+	statement := NewStatement(from, from.AddDate(0, 1, 0), types.Decimal{}, points(100), nil, page, 2)
+
+	assert.Nil(t, statement.Next)
+	assert.Equal(t, "70", statement.Entries[0].Balance.String())
+}
+
+func TestStatementCursorRoundTrip(t *testing.T) {
+	cursor := StatementCursor{CreatedAt: time.Date(2025, time.May, 1, 10, 30, 0, 123456000, time.UTC), ID: 42}
+
+	parsed, err := ParseStatementCursor(cursor.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, *parsed)
+
+	_, err = ParseStatementCursor("not a cursor")
+	assert.Equal(t, ErrInvalidCursor, err)
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// TierWindowMonths is the rolling period the tier thresholds are evaluated on.
+const TierWindowMonths = 12
+
+type Tier struct {
+	ID         int
+	Name       string
+	Threshold  types.Decimal
+	Multiplier types.Decimal
+	Perks      []string
+}
+
+// Bonus returns the extra points the tier grants on top of an accrual.
+func (t *Tier) Bonus(accrual types.Decimal) types.Decimal {
+	if t == nil {
+		return types.Decimal{}
+	}
+	extra := t.Multiplier.Decimal.Sub(decimal.NewFromInt(1))
+	if !extra.IsPositive() {
+		return types.Decimal{}
+	}
+	return types.Decimal{Decimal: accrual.Decimal.Mul(extra).Round(types.PointsScale)}
+}
+
+// ResolveTier picks the highest tier whose threshold is reached, tiers must be ordered by threshold.
+func ResolveTier(tiers []*Tier, accrued types.Decimal) *Tier {
+	var result *Tier
+	for _, tier := range tiers {
+		if accrued.Cmp(tier.Threshold) >= 0 {
+			result = tier
+		}
+	}
+	return result
+}
+
+type TierProgress struct {
+	Current        *Tier
+	Next           *Tier
+	RollingAccrued types.Decimal
+	Remaining      types.Decimal
+}
+
+func NewTierProgress(tiers []*Tier, current *Tier, accrued types.Decimal) *TierProgress {
+	progress := &TierProgress{Current: current, RollingAccrued: accrued}
+	for _, tier := range tiers {
+		if current != nil && tier.Threshold.Cmp(current.Threshold) <= 0 {
+			continue
+		}
+		if accrued.Cmp(tier.Threshold) < 0 {
+			progress.Next = tier
+			progress.Remaining = tier.Threshold.Sub(accrued)
+			break
+		}
+	}
+	return progress
+}
+
+type TierHistory struct {
+	UserID         int64
+	ChangedAt      time.Time
+	PreviousTierID *int
+	TierID         *int
+	RollingAccrued types.Decimal
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func points(value float64) types.Decimal {
+	return types.Decimal{Decimal: decimal.NewFromFloat(value)}
+}
+
+func TestTierProgress(t *testing.T) {
+	tiers := []*Tier{
+		{ID: 1, Name: "Silver", Threshold: points(1000), Multiplier: points(1.05)},
+		{ID: 2, Name: "Gold", Threshold: points(5000), Multiplier: points(1.1)},
+	}
+	cases := []struct {
+		name              string
+		accrued           types.Decimal
+		expectedTier      *Tier
+		expectedNext      *Tier
+		expectedRemaining string
+	}{
+		{
+			name:              "no tier",
+			accrued:           points(400),
+			expectedNext:      tiers[0],
+			expectedRemaining: "600",
+		},
+		{
+			name:              "silver",
+			accrued:           points(1200),
+			expectedTier:      tiers[0],
+			expectedNext:      tiers[1],
+			expectedRemaining: "3800",
+		},
+		{
+			name:              "top tier",
+			accrued:           points(7000),
+			expectedTier:      tiers[1],
+			expectedRemaining: "0",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tier := ResolveTier(tiers, c.accrued)
+			assert.Equal(t, c.expectedTier, tier)
+			progress := NewTierProgress(tiers, tier, c.accrued)
+			assert.Equal(t, c.expectedNext, progress.Next)
+			assert.Equal(t, c.expectedRemaining, progress.Remaining.String())
+		})
+	}
+}
+
+func TestTierBonus(t *testing.T) {
+	tier := &Tier{Multiplier: points(1.05)}
+	assert.Equal(t, "25.01", tier.Bonus(points(500.25)).String())
+
+	var none *Tier
+	assert.True(t, none.Bonus(points(500)).IsZero())
+}
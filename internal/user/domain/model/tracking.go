@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+// ErrLeaseLost is returned when a checked order is saved after its lease ran out.
+var ErrLeaseLost = errors.New("order lease expired")
+
+var (
+	ErrNotDeadLettered = errors.New("order is not dead-lettered")
+	ErrResolveStatus   = errors.New("dead-lettered orders can only be resolved as PROCESSED or INVALID")
+)
+
+// ErrorClass groups failed checks by cause, so dead-lettered orders can be triaged without reading every message.
+type ErrorClass string
+
+const (
+	ErrorClassNotRegistered ErrorClass = "NOT_REGISTERED"
+	ErrorClassTransport     ErrorClass = "TRANSPORT"
+	ErrorClassServer        ErrorClass = "SERVER"
+	ErrorClassClient        ErrorClass = "CLIENT"
+	ErrorClassMalformed     ErrorClass = "MALFORMED_RESPONSE"
+	ErrorClassInvalidAmount ErrorClass = "INVALID_AMOUNT"
+	// ErrorClassQuarantined is a result that failed validation and was put aside for review.
+	ErrorClassQuarantined ErrorClass = "QUARANTINED"
+)
+
+// Lease is a time-limited claim of an instance on orders it checks with the accrual service.
+type Lease struct {
+	Owner string
+	// At is when the lease is taken or used, orders due by then are claimed.
+	At    time.Time
+	Until time.Time
+}
+
+// TrackingPolicy decides when an order is checked with the accrual service again.
+type TrackingPolicy struct {
+	// Registered is the interval for orders the accrual service has not started on yet.
+	Registered time.Duration
+	// Processing is the interval for orders the accrual service is working on.
+	Processing time.Duration
+	// ErrorBase is the first delay after a failed check, it doubles with every failure in a row.
+	ErrorBase time.Duration
+	// ErrorMax caps the delay after failed checks.
+	ErrorMax time.Duration
+	// Lease is how long claimed orders stay reserved, it has to cover checking a whole batch.
+	Lease time.Duration
+	// MaxAttempts dead-letters an order after that many failed checks in a row, 0 retries forever.
+	MaxAttempts int
+	// MaxAccrual is the largest accrual a single order may get, larger results are quarantined.
+	// Zero only keeps the limit of the points column.
+	MaxAccrual types.Decimal
+}
+
+// Backoff returns the delay after the given number of failed checks in a row.
+func (p TrackingPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	delay := p.ErrorBase
+	// the doubling stops well before the duration overflows when no cap is set
+	for i := 1; i < attempts && i < 32; i++ {
+		delay *= 2
+		if p.ErrorMax > 0 && delay >= p.ErrorMax {
+			return p.ErrorMax
+		}
+	}
+	if p.ErrorMax > 0 && delay > p.ErrorMax {
+		return p.ErrorMax
+	}
+	return delay
+}
+
+// Schedule sets the next check of the order after it was checked at now.
+// Failed checks, including orders unknown to the accrual service, back off exponentially.
+func (o *Order) Schedule(p TrackingPolicy, now time.Time) {
+	if o.Error != "" {
+		o.Attempts++
+		o.NextCheckAt = now.Add(p.Backoff(o.Attempts))
+		if p.MaxAttempts > 0 && o.Attempts >= p.MaxAttempts {
+			o.DeadLetteredAt = &now
+		}
+		return
+	}
+	o.ErrorClass = ""
+	o.Attempts = 0
+	o.DeadLetteredAt = nil
+	switch o.Status {
+	case OrderStatusNEW:
+		o.NextCheckAt = now.Add(p.Registered)
+	case OrderStatusPROCESSING:
+		o.NextCheckAt = now.Add(p.Processing)
+	default:
+		o.NextCheckAt = now
+	}
+}
+
+// Resolve settles a dead-lettered order by hand, a PROCESSED order is credited with the accrual.
+func (o *Order) Resolve(status OrderStatus, accrual types.Decimal) error {
+	if o.DeadLetteredAt == nil {
+		return ErrNotDeadLettered
+	}
+	switch status {
+	case OrderStatusPROCESSED:
+		if accrual.IsPositive() {
+			o.AddTransaction(ACCRUAL, accrual)
+		}
+	case OrderStatusINVALID:
+	default:
+		return ErrResolveStatus
+	}
+	o.Status = status
+	o.Error = ""
+	o.ErrorClass = ""
+	o.DeadLetteredAt = nil
+	return nil
+}
+
+// Requeue puts a dead-lettered order back into automatic tracking with a clean slate.
+func (o *Order) Requeue(now time.Time) error {
+	if o.DeadLetteredAt == nil {
+		return ErrNotDeadLettered
+	}
+	o.Attempts = 0
+	o.Error = ""
+	o.ErrorClass = ""
+	o.NextCheckAt = now
+	o.DeadLetteredAt = nil
+	return nil
+}
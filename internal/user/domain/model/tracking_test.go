@@ -0,0 +1,144 @@
+package model
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOrderSchedule(t *testing.T) {
+	policy := TrackingPolicy{
+		Registered: 10 * time.Second,
+		Processing: time.Minute,
+		ErrorBase:  30 * time.Second,
+		ErrorMax:   5 * time.Minute,
+	}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name             string
+		order            Order
+		expectedDelay    time.Duration
+		expectedAttempts int
+	}{
+		{
+			name:          "registered",
+			order:         Order{Status: OrderStatusNEW, Attempts: 2},
+			expectedDelay: 10 * time.Second,
+		},
+		{
+			name:          "processing",
+			order:         Order{Status: OrderStatusPROCESSING},
+			expectedDelay: time.Minute,
+		},
+		{
+			name:             "first error",
+			order:            Order{Status: OrderStatusNEW, Error: "Order not found"},
+			expectedDelay:    30 * time.Second,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "third error",
+			order:            Order{Status: OrderStatusPROCESSING, Error: "502 Bad Gateway", Attempts: 2},
+			expectedDelay:    2 * time.Minute,
+			expectedAttempts: 3,
+		},
+		{
+			name:             "capped",
+			order:            Order{Status: OrderStatusNEW, Error: "502 Bad Gateway", Attempts: 20},
+			expectedDelay:    5 * time.Minute,
+			expectedAttempts: 21,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.order.Schedule(policy, now)
+			assert.Equal(t, now.Add(c.expectedDelay), c.order.NextCheckAt)
+			assert.Equal(t, c.expectedAttempts, c.order.Attempts)
+		})
+	}
+}
+
+func TestOrderScheduleShouldDeadLetterAfterMaxAttempts(t *testing.T) {
+	policy := TrackingPolicy{ErrorBase: 30 * time.Second, MaxAttempts: 3}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{Status: OrderStatusNEW, Error: "502 Bad Gateway", ErrorClass: ErrorClassServer, Attempts: 1}
+
+	order.Schedule(policy, now)
+	assert.Nil(t, order.DeadLetteredAt)
+
+	order.Schedule(policy, now)
+	assert.Equal(t, 3, order.Attempts)
+	assert.Equal(t, &now, order.DeadLetteredAt)
+	assert.Equal(t, ErrorClassServer, order.ErrorClass)
+}
+
+func TestOrderResolve(t *testing.T) {
+	deadLetteredAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name                 string
+		deadLetteredAt       *time.Time
+		status               OrderStatus
+		accrual              types.Decimal
+		expectedErr          error
+		expectedTransactions int
+	}{
+		{
+			name:                 "processed",
+			deadLetteredAt:       &deadLetteredAt,
+			status:               OrderStatusPROCESSED,
+			accrual:              types.Decimal{Decimal: decimal.NewFromInt(500)},
+			expectedTransactions: 1,
+		},
+		{
+			name:           "processed without accrual",
+			deadLetteredAt: &deadLetteredAt,
+			status:         OrderStatusPROCESSED,
+		},
+		{
+			name:           "invalid",
+			deadLetteredAt: &deadLetteredAt,
+			status:         OrderStatusINVALID,
+		},
+		{
+			name:           "processing",
+			deadLetteredAt: &deadLetteredAt,
+			status:         OrderStatusPROCESSING,
+			expectedErr:    ErrResolveStatus,
+		},
+		{
+			name:        "tracked",
+			status:      OrderStatusPROCESSED,
+			expectedErr: ErrNotDeadLettered,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			order := Order{Status: OrderStatusNEW, Error: "502 Bad Gateway", ErrorClass: ErrorClassServer,
+				Attempts: 10, DeadLetteredAt: c.deadLetteredAt}
+			err := order.Resolve(c.status, c.accrual)
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Len(t, order.Transactions(), c.expectedTransactions)
+			if c.expectedErr != nil {
+				assert.Equal(t, OrderStatusNEW, order.Status)
+				return
+			}
+			assert.Equal(t, c.status, order.Status)
+			assert.Empty(t, order.Error)
+			assert.Empty(t, order.ErrorClass)
+			assert.Nil(t, order.DeadLetteredAt)
+		})
+	}
+}
+
+func TestOrderRequeueShouldResetAttempts(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{Status: OrderStatusPROCESSING, Error: "502 Bad Gateway", Attempts: 10, DeadLetteredAt: &now}
+
+	assert.NoError(t, order.Requeue(now.Add(time.Hour)))
+	assert.Equal(t, 0, order.Attempts)
+	assert.Equal(t, now.Add(time.Hour), order.NextCheckAt)
+	assert.Nil(t, order.DeadLetteredAt)
+	assert.ErrorIs(t, order.Requeue(now), ErrNotDeadLettered)
+}
@@ -9,25 +9,121 @@ import (
 
 type TransactionType int
 
-var ErrBonusTransaction = errors.New("invalid transaction")
+var (
+	ErrBonusTransaction    = errors.New("invalid transaction")
+	ErrNotReversible       = errors.New("transaction can not be reversed")
+	ErrInvalidReason       = errors.New("invalid reason code")
+	ErrTransactionReversed = errors.New("transaction already reversed")
+	ErrTransactionType     = errors.New("invalid transaction type")
+	// ErrAlreadyCredited is returned when an order is credited again with a transaction it may only get once.
+	ErrAlreadyCredited = errors.New("order is already credited")
+)
 
 const (
 	ACCRUAL TransactionType = iota
 	WITHDRAWAL
+	REFUND
+	REVERSAL
+	EXPIRATION
+	BONUS
+	TRANSFER_OUT
+	TRANSFER_IN
+	PROMO
 )
 
+var transactionTypeNames = [...]string{"ACCRUAL", "WITHDRAWAL", "REFUND", "REVERSAL", "EXPIRATION", "BONUS", "TRANSFER_OUT", "TRANSFER_IN", "PROMO"}
+
 func (s *TransactionType) String() string {
-	return [...]string{"ACCRUAL", "WITHDRAWAL"}[*s]
+	return transactionTypeNames[*s]
+}
+
+func ParseTransactionType(name string) (TransactionType, error) {
+	for i, typeName := range transactionTypeNames {
+		if typeName == name {
+			return TransactionType(i), nil
+		}
+	}
+	return 0, ErrTransactionType
 }
 
 func (s *TransactionType) Value() (driver.Value, error) {
 	return int64(*s), nil
 }
 
+// IsCredit reports whether the transaction increases the current balance.
+func (s TransactionType) IsCredit() bool {
+	switch s {
+	case ACCRUAL, REFUND, BONUS, TRANSFER_IN, PROMO:
+		return true
+	}
+	return false
+}
+
+// ConsumesLots reports whether the transaction spends points from the oldest lots first.
+// Expirations are excluded: they close a specific lot instead.
+func (s TransactionType) ConsumesLots() bool {
+	switch s {
+	case WITHDRAWAL, REVERSAL, TRANSFER_OUT:
+		return true
+	}
+	return false
+}
+
+// OncePerOrder reports whether an order gets at most one transaction of the type, the database enforces it.
+func (s TransactionType) OncePerOrder() bool {
+	return s == ACCRUAL || s == BONUS
+}
+
+type ReasonCode string
+
+const (
+	ReasonGoodsReturned    ReasonCode = "GOODS_RETURNED"
+	ReasonOrderCancelled   ReasonCode = "ORDER_CANCELLED"
+	ReasonAccrualCorrected ReasonCode = "ACCRUAL_CORRECTED"
+	ReasonFraud            ReasonCode = "FRAUD"
+	ReasonOther            ReasonCode = "OTHER"
+)
+
+func NewReasonCode(value string) (ReasonCode, error) {
+	switch code := ReasonCode(value); code {
+	case ReasonGoodsReturned, ReasonOrderCancelled, ReasonAccrualCorrected, ReasonFraud, ReasonOther:
+		return code, nil
+	}
+	return "", ErrInvalidReason
+}
+
 type Transaction struct {
-	UserID    int64
-	CreatedAt time.Time
-	Type      TransactionType
-	Amount    types.Decimal
-	OrderID   OrderID
+	ID          int64
+	UserID      int64
+	CreatedAt   time.Time
+	Type        TransactionType
+	Amount      types.Decimal
+	OrderID     OrderID
+	ReferenceID *int64
+	Reason      ReasonCode
+	TransferID  *int64
+	Note        string
+}
+
+// Reverse builds the compensating movement for the transaction: withdrawals
+// are given back with REFUND, accruals and the tier bonuses on top of them are clawed back with REVERSAL.
+func (t *Transaction) Reverse(reason ReasonCode) (*Transaction, error) {
+	var tt TransactionType
+	switch t.Type {
+	case WITHDRAWAL:
+		tt = REFUND
+	case ACCRUAL, BONUS:
+		tt = REVERSAL
+	default:
+		return nil, ErrNotReversible
+	}
+	referenceID := t.ID
+	return &Transaction{
+		UserID:      t.UserID,
+		Type:        tt,
+		Amount:      t.Amount,
+		OrderID:     t.OrderID,
+		ReferenceID: &referenceID,
+		Reason:      reason,
+	}, nil
 }
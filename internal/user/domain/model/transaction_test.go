@@ -0,0 +1,68 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransactionReverse(t *testing.T) {
+	cases := []struct {
+		name         string
+		tt           TransactionType
+		expectedType TransactionType
+		expectedErr  error
+	}{
+		{
+			name:         "withdrawal is refunded",
+			tt:           WITHDRAWAL,
+			expectedType: REFUND,
+		},
+		{
+			name:         "accrual is reversed",
+			tt:           ACCRUAL,
+			expectedType: REVERSAL,
+		},
+		{
+			name:         "bonus is reversed",
+			tt:           BONUS,
+			expectedType: REVERSAL,
+		},
+		{
+			name:        "refund is not reversible",
+			tt:          REFUND,
+			expectedErr: ErrNotReversible,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr := &Transaction{ID: 7, UserID: 1, Type: c.tt, OrderID: OrderID{Value: 9278923470}}
+			v, err := tr.Reverse(ReasonGoodsReturned)
+			assert.Equal(t, c.expectedErr, err)
+			if c.expectedErr != nil {
+				return
+			}
+			assert.Equal(t, c.expectedType, v.Type)
+			assert.Equal(t, int64(7), *v.ReferenceID)
+			assert.Equal(t, tr.OrderID, v.OrderID)
+			assert.Equal(t, ReasonGoodsReturned, v.Reason)
+		})
+	}
+}
+
+func TestTransactionTypeOncePerOrder(t *testing.T) {
+	cases := []struct {
+		tt       TransactionType
+		expected bool
+	}{
+		{tt: ACCRUAL, expected: true},
+		{tt: BONUS, expected: true},
+		{tt: WITHDRAWAL},
+		{tt: REVERSAL},
+		{tt: PROMO},
+	}
+	for _, c := range cases {
+		t.Run(c.tt.String(), func(t *testing.T) {
+			assert.Equal(t, c.expected, c.tt.OncePerOrder())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+var (
+	ErrTransferToSelf     = errors.New("points can not be transferred to yourself")
+	ErrTransferAmount     = errors.New("transfer amount must be positive")
+	ErrTransferDailyLimit = errors.New("daily transfer limit exceeded")
+)
+
+type Transfer struct {
+	ID          int64
+	CreatedAt   time.Time
+	SenderID    int64
+	RecipientID int64
+	Amount      types.Decimal
+	Note        string
+}
+
+// TransferLimits caps what a sender can transfer per day, zero values disable a cap.
+type TransferLimits struct {
+	DailyAmount types.Decimal
+	DailyCount  int
+}
+
+// Check validates the transfer against what the sender has already transferred today.
+func (l TransferLimits) Check(amount, transferred types.Decimal, count int) error {
+	if l.DailyCount > 0 && count+1 > l.DailyCount {
+		return ErrTransferDailyLimit
+	}
+	total := transferred.Add(amount)
+	if l.DailyAmount.IsPositive() && total.Cmp(l.DailyAmount) > 0 {
+		return ErrTransferDailyLimit
+	}
+	return nil
+}
+
+// Entries builds the paired debit and credit movements of the transfer.
+func (t *Transfer) Entries() (*Transaction, *Transaction) {
+	transferID := t.ID
+	debit := &Transaction{
+		UserID:     t.SenderID,
+		Type:       TRANSFER_OUT,
+		Amount:     t.Amount,
+		TransferID: &transferID,
+		Note:       t.Note,
+	}
+	credit := &Transaction{
+		UserID:     t.RecipientID,
+		Type:       TRANSFER_IN,
+		Amount:     t.Amount,
+		TransferID: &transferID,
+		Note:       t.Note,
+	}
+	return debit, credit
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransferLimits(t *testing.T) {
+	cases := []struct {
+		name        string
+		limits      TransferLimits
+		transferred float64
+		count       int
+		expectedErr error
+	}{
+		{
+			name:   "no limits",
+			limits: TransferLimits{},
+			count:  100,
+		},
+		{
+			name:        "within amount",
+			limits:      TransferLimits{DailyAmount: points(500)},
+			transferred: 400,
+		},
+		{
+			name:        "amount exceeded",
+			limits:      TransferLimits{DailyAmount: points(500)},
+			transferred: 450,
+			expectedErr: ErrTransferDailyLimit,
+		},
+		{
+			name:        "count exceeded",
+			limits:      TransferLimits{DailyCount: 3},
+			count:       3,
+			expectedErr: ErrTransferDailyLimit,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.limits.Check(points(100), points(c.transferred), c.count)
+			assert.Equal(t, c.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+const (
+	CodeInsufficientBalance = "INSUFFICIENT_BALANCE"
+	CodeBelowMinimum        = "WITHDRAWAL_BELOW_MINIMUM"
+	CodeAboveMaximum        = "WITHDRAWAL_ABOVE_MAXIMUM"
+	CodeDailyCapExceeded    = "DAILY_CAP_EXCEEDED"
+	CodeMonthlyCapExceeded  = "MONTHLY_CAP_EXCEEDED"
+	CodeOrderShareExceeded  = "ORDER_SHARE_EXCEEDED"
+	CodeOrderTotalRequired  = "ORDER_TOTAL_REQUIRED"
+	CodeCoolingOffPeriod    = "COOLING_OFF_PERIOD"
+	CodeNoAccrualsYet       = "NO_ACCRUALS_YET"
+)
+
+// ErrWithdrawalAmount is returned for a withdrawal of zero points, including a request without a sum.
+var ErrWithdrawalAmount = errors.New("withdrawal sum must be positive")
+
+// PolicyViolation is returned when a withdrawal breaks one of the rules, Code is stable for clients to rely on.
+type PolicyViolation struct {
+	Code    string
+	Message string
+}
+
+func (v *PolicyViolation) Error() string {
+	return v.Message
+}
+
+func newViolation(code, format string, args ...any) *PolicyViolation {
+	return &PolicyViolation{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// WithdrawalRules limit withdrawals, zero values disable a rule. MaxOrderShare is a fraction of the order total.
+type WithdrawalRules struct {
+	MinAmount     types.Decimal
+	MaxAmount     types.Decimal
+	DailyCap      types.Decimal
+	MonthlyCap    types.Decimal
+	MaxOrderShare types.Decimal
+	CoolingOff    time.Duration
+}
+
+// WithdrawalRuleOverride replaces the rules that are set for a tier or a single user, nil fields are inherited.
+type WithdrawalRuleOverride struct {
+	ID            int64
+	UserID        *int64
+	TierID        *int
+	MinAmount     *types.Decimal
+	MaxAmount     *types.Decimal
+	DailyCap      *types.Decimal
+	MonthlyCap    *types.Decimal
+	MaxOrderShare *types.Decimal
+	CoolingOff    *time.Duration
+}
+
+// WithdrawalUsage is what the user has already withdrawn, refunds are deducted.
+type WithdrawalUsage struct {
+	Day            types.Decimal
+	Month          types.Decimal
+	FirstAccrualAt *time.Time
+}
+
+// Apply returns the rules with the override on top of them.
+func (r WithdrawalRules) Apply(o *WithdrawalRuleOverride) WithdrawalRules {
+	if o == nil {
+		return r
+	}
+	if o.MinAmount != nil {
+		r.MinAmount = *o.MinAmount
+	}
+	if o.MaxAmount != nil {
+		r.MaxAmount = *o.MaxAmount
+	}
+	if o.DailyCap != nil {
+		r.DailyCap = *o.DailyCap
+	}
+	if o.MonthlyCap != nil {
+		r.MonthlyCap = *o.MonthlyCap
+	}
+	if o.MaxOrderShare != nil {
+		r.MaxOrderShare = *o.MaxOrderShare
+	}
+	if o.CoolingOff != nil {
+		r.CoolingOff = *o.CoolingOff
+	}
+	return r
+}
+
+// Check validates the withdrawal of amount at the given moment, orderTotal is only required by the order share rule.
+func (r WithdrawalRules) Check(amount types.Decimal, orderTotal *types.Decimal, usage *WithdrawalUsage, at time.Time) error {
+	if r.MinAmount.IsPositive() && amount.Cmp(r.MinAmount) < 0 {
+		return newViolation(CodeBelowMinimum, "withdrawal must be at least %s points", r.MinAmount.String())
+	}
+	if r.MaxAmount.IsPositive() && amount.Cmp(r.MaxAmount) > 0 {
+		return newViolation(CodeAboveMaximum, "withdrawal must not exceed %s points", r.MaxAmount.String())
+	}
+	if r.CoolingOff > 0 {
+		if usage.FirstAccrualAt == nil {
+			return newViolation(CodeNoAccrualsYet, "withdrawals are allowed after the first accrual")
+		}
+		if availableAt := usage.FirstAccrualAt.Add(r.CoolingOff); at.Before(availableAt) {
+			return newViolation(CodeCoolingOffPeriod, "withdrawals are allowed from %s", availableAt.Format(time.RFC3339))
+		}
+	}
+	day := usage.Day.Add(amount)
+	if r.DailyCap.IsPositive() && day.Cmp(r.DailyCap) > 0 {
+		return newViolation(CodeDailyCapExceeded, "daily withdrawal cap of %s points exceeded", r.DailyCap.String())
+	}
+	month := usage.Month.Add(amount)
+	if r.MonthlyCap.IsPositive() && month.Cmp(r.MonthlyCap) > 0 {
+		return newViolation(CodeMonthlyCapExceeded, "monthly withdrawal cap of %s points exceeded", r.MonthlyCap.String())
+	}
+	if r.MaxOrderShare.IsPositive() {
+		if orderTotal == nil || !orderTotal.IsPositive() {
+			return newViolation(CodeOrderTotalRequired, "order total is required to check the share payable with points")
+		}
+		limit := types.Decimal{Decimal: orderTotal.Decimal.Mul(r.MaxOrderShare.Decimal)}
+		if amount.Cmp(limit) > 0 {
+			return newViolation(CodeOrderShareExceeded, "at most %s points can be spent on this order", limit.Round(2).String())
+		}
+	}
+	return nil
+}
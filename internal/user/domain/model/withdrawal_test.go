@@ -0,0 +1,119 @@
+package model
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWithdrawalRulesCheck(t *testing.T) {
+	now := time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+	accruedAt := now.Add(-48 * time.Hour)
+	total := points(1000)
+	cases := []struct {
+		name         string
+		rules        WithdrawalRules
+		amount       float64
+		orderTotal   *types.Decimal
+		usage        WithdrawalUsage
+		expectedCode string
+	}{
+		{
+			name:   "no rules",
+			amount: 100000,
+		},
+		{
+			name:         "below minimum",
+			rules:        WithdrawalRules{MinAmount: points(50)},
+			amount:       10,
+			expectedCode: CodeBelowMinimum,
+		},
+		{
+			name:         "above maximum",
+			rules:        WithdrawalRules{MaxAmount: points(500)},
+			amount:       501,
+			expectedCode: CodeAboveMaximum,
+		},
+		{
+			name:         "daily cap",
+			rules:        WithdrawalRules{DailyCap: points(300)},
+			amount:       100,
+			usage:        WithdrawalUsage{Day: points(250), Month: points(250)},
+			expectedCode: CodeDailyCapExceeded,
+		},
+		{
+			name:         "monthly cap",
+			rules:        WithdrawalRules{DailyCap: points(300), MonthlyCap: points(1000)},
+			amount:       100,
+			usage:        WithdrawalUsage{Month: points(950)},
+			expectedCode: CodeMonthlyCapExceeded,
+		},
+		{
+			name:         "order total missing",
+			rules:        WithdrawalRules{MaxOrderShare: points(0.5)},
+			amount:       100,
+			expectedCode: CodeOrderTotalRequired,
+		},
+		{
+			name:         "order share exceeded",
+			rules:        WithdrawalRules{MaxOrderShare: points(0.5)},
+			amount:       600,
+			orderTotal:   &total,
+			expectedCode: CodeOrderShareExceeded,
+		},
+		{
+			name:       "within order share",
+			rules:      WithdrawalRules{MaxOrderShare: points(0.5)},
+			amount:     500,
+			orderTotal: &total,
+		},
+		{
+			name:         "no accruals",
+			rules:        WithdrawalRules{CoolingOff: time.Hour},
+			amount:       100,
+			expectedCode: CodeNoAccrualsYet,
+		},
+		{
+			name:         "cooling off",
+			rules:        WithdrawalRules{CoolingOff: 72 * time.Hour},
+			amount:       100,
+			usage:        WithdrawalUsage{FirstAccrualAt: &accruedAt},
+			expectedCode: CodeCoolingOffPeriod,
+		},
+		{
+			name:   "cooling off passed",
+			rules:  WithdrawalRules{CoolingOff: 24 * time.Hour},
+			amount: 100,
+			usage:  WithdrawalUsage{FirstAccrualAt: &accruedAt},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.rules.Check(points(c.amount), c.orderTotal, &c.usage, now)
+			if c.expectedCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var violation *PolicyViolation
+			assert.ErrorAs(t, err, &violation)
+			assert.Equal(t, c.expectedCode, violation.Code)
+		})
+	}
+}
+
+func TestWithdrawalRulesApply(t *testing.T) {
+	global := WithdrawalRules{MinAmount: points(10), DailyCap: points(1000)}
+	tierCap := points(5000)
+	userMin := points(1)
+	coolingOff := 24 * time.Hour
+
+	rules := global.
+		Apply(&WithdrawalRuleOverride{DailyCap: &tierCap}).
+		Apply(&WithdrawalRuleOverride{MinAmount: &userMin, CoolingOff: &coolingOff})
+
+	assert.Equal(t, "1", rules.MinAmount.String())
+	assert.Equal(t, "5000", rules.DailyCap.String())
+	assert.Equal(t, coolingOff, rules.CoolingOff)
+	assert.True(t, rules.MonthlyCap.IsZero(), "rules missing in every override stay disabled")
+}
@@ -7,9 +7,11 @@ import (
 )
 
 type OrderService interface {
-	Upload(ctx context.Context, number model.OrderID) (bool, error)
+	// Upload registers the order for tracking with the requested accrual provider, an empty provider
+	// leaves the choice to the routing rules.
+	Upload(ctx context.Context, number model.OrderID, provider string) (bool, error)
 
 	List(ctx context.Context) ([]*model.Order, error)
 
-	Withdraw(ctx context.Context, number model.OrderID, decimal types.Decimal) error
+	Withdraw(ctx context.Context, number model.OrderID, decimal types.Decimal, orderTotal *types.Decimal) error
 }
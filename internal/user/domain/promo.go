@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type PromoService interface {
+	Create(ctx context.Context, promo *model.PromoCode) (*model.PromoCode, error)
+
+	Generate(ctx context.Context, template *model.PromoCode, prefix string, count int) ([]*model.PromoCode, error)
+
+	List(ctx context.Context, limit, offset int) ([]*model.PromoCode, error)
+
+	Redeem(ctx context.Context, code string) (*model.Transaction, error)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type QuarantineService interface {
+	// List returns the results waiting for review, the latest first.
+	List(ctx context.Context, limit, offset int) ([]*model.QuarantinedResult, error)
+
+	Get(ctx context.Context, id int64) (*model.QuarantinedResult, error)
+
+	// Review closes a quarantined result with a note. The order itself keeps being tracked
+	// and ends up dead-lettered when the accrual service keeps sending bad results.
+	Review(ctx context.Context, id int64, note string) (*model.QuarantinedResult, error)
+}
@@ -3,8 +3,15 @@ package repository
 import (
 	"context"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
 )
 
 type BonusBalanceRepository interface {
 	Get(ctx context.Context, userID int64) (*model.BonusBalance, error)
+
+	// GetAt computes the balance as of the given moment from the latest snapshot preceding it.
+	GetAt(ctx context.Context, userID int64, at time.Time) (*model.BonusBalance, error)
+
+	// Snapshot stores the balances of users with movements since their previous snapshot up to cutoff.
+	Snapshot(ctx context.Context, cutoff time.Time) (int64, error)
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ExportRepository interface {
+	Insert(ctx context.Context, export *model.Export) (int64, error)
+
+	Get(ctx context.Context, id, userID int64) (*model.Export, error)
+
+	// Claim marks the oldest new export, or one stuck in processing since staleBefore, as processing.
+	Claim(ctx context.Context, staleBefore time.Time) (*model.Export, error)
+
+	Progress(ctx context.Context, export *model.Export) error
+
+	Update(ctx context.Context, export *model.Export) error
+
+	// GetExpired returns generated exports whose download link expired before the given moment.
+	GetExpired(ctx context.Context, at time.Time) ([]*model.Export, error)
+}
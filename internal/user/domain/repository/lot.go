@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type LotRepository interface {
+	GetExpired(ctx context.Context, cutoff time.Time, limit, offset int) ([]*model.Lot, error)
+
+	// GetExpiredOwners returns up to limit users holding lots created before cutoff.
+	GetExpiredOwners(ctx context.Context, cutoff time.Time, limit int) ([]int64, error)
+
+	// GetExpiredForUpdate locks the lots of the user created before cutoff.
+	GetExpiredForUpdate(ctx context.Context, userID int64, cutoff time.Time) ([]*model.Lot, error)
+
+	GetExpiring(ctx context.Context, userID int64, cutoff time.Time) ([]*model.Lot, error)
+
+	Expire(ctx context.Context, lot *model.Lot) error
+}
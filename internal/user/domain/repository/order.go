@@ -10,11 +10,35 @@ type OrderRepository interface {
 
 	Get(ctx context.Context, id model.OrderID) (*model.Order, error)
 
-	GetForUpdate(ctx context.Context, limit, offset int, status ...model.OrderStatus) ([]*model.Order, error)
+	GetForUpdate(ctx context.Context, id model.OrderID) (*model.Order, error)
+
+	// Claim leases up to limit orders in the given statuses that are due at lease.At, the longest waiting first.
+	Claim(ctx context.Context, lease model.Lease, limit int, status ...model.OrderStatus) ([]*model.Order, error)
+
+	// UpdateLeased saves the checked order and its transactions, it fails with model.ErrLeaseLost
+	// when the lease expired and the order may already be claimed by someone else.
+	UpdateLeased(ctx context.Context, order *model.Order, lease model.Lease) error
+
+	// UpdateChecked saves an order checked without a lease, e.g. pushed by the accrual service, together with
+	// its transactions. Any lease on the order is dropped, so a concurrent check fails with model.ErrLeaseLost.
+	UpdateChecked(ctx context.Context, order *model.Order) error
+
+	// Release gives orders back to the queue unchanged.
+	Release(ctx context.Context, ids []model.OrderID, owner string) error
 
 	GetAll(ctx context.Context, userID int64) ([]*model.Order, error)
 
 	Insert(ctx context.Context, order *model.Order) (model.OrderID, error)
 
 	Update(ctx context.Context, order *model.Order) error
+
+	// GetDeadLettered returns a page of orders taken out of tracking, the latest first.
+	GetDeadLettered(ctx context.Context, limit, offset int) ([]*model.Order, error)
+
+	// UpdateDeadLettered saves a requeued or resolved order and its transactions, it fails with
+	// model.ErrNotDeadLettered when the order was handled in the meantime.
+	UpdateDeadLettered(ctx context.Context, order *model.Order) error
+
+	// NotifyUploaded tells listening workers that the order is waiting for its first check.
+	NotifyUploaded(ctx context.Context, id model.OrderID) error
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type PromoCodeRepository interface {
+	Exists(ctx context.Context, code string) (bool, error)
+
+	GetAll(ctx context.Context, limit, offset int) ([]*model.PromoCode, error)
+
+	GetForUpdate(ctx context.Context, code string) (*model.PromoCode, error)
+
+	Insert(ctx context.Context, promo *model.PromoCode) (int64, error)
+
+	UserRedemptions(ctx context.Context, promoID, userID int64) (int, error)
+
+	Redeem(ctx context.Context, promo *model.PromoCode, userID, transactionID int64) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type QuarantineRepository interface {
+	Insert(ctx context.Context, result *model.QuarantinedResult) (int64, error)
+
+	Get(ctx context.Context, id int64) (*model.QuarantinedResult, error)
+
+	// GetPending returns a page of results nobody reviewed yet, the latest first.
+	GetPending(ctx context.Context, limit, offset int) ([]*model.QuarantinedResult, error)
+
+	// Review saves the review of a result, it fails with model.ErrQuarantineReviewed
+	// when the result was reviewed in the meantime.
+	Review(ctx context.Context, result *model.QuarantinedResult) error
+}
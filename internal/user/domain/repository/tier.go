@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type TierRepository interface {
+	GetAll(ctx context.Context) ([]*model.Tier, error)
+
+	GetByUser(ctx context.Context, userID int64) (*model.Tier, error)
+
+	GetHistory(ctx context.Context, userID int64) ([]*model.TierHistory, error)
+
+	RollingAccrued(ctx context.Context, userID int64, since time.Time) (types.Decimal, error)
+
+	Change(ctx context.Context, history *model.TierHistory) error
+}
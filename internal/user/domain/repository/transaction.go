@@ -2,9 +2,27 @@ package repository
 
 import (
 	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
 )
 
 type TransactionRepository interface {
 	GetAll(ctx context.Context, userID int64, tt *model.TransactionType) ([]*model.Transaction, error)
+
+	GetForUpdate(ctx context.Context, id int64) (*model.Transaction, error)
+
+	GetByOrderForUpdate(ctx context.Context, orderID model.OrderID, tt model.TransactionType) (*model.Transaction, error)
+
+	IsReversed(ctx context.Context, id int64) (bool, error)
+
+	Insert(ctx context.Context, transaction *model.Transaction) (int64, error)
+
+	// BalanceBefore sums the movements of the user preceding the cursor.
+	BalanceBefore(ctx context.Context, userID int64, cursor model.StatementCursor) (types.Decimal, error)
+
+	// GetPage returns up to limit movements following the cursor and created before to, no types means every type.
+	GetPage(ctx context.Context, userID int64, after model.StatementCursor, to time.Time, tt []model.TransactionType, limit int) ([]*model.Transaction, error)
+
+	Totals(ctx context.Context, userID int64, from, to time.Time) ([]*model.StatementTotal, error)
 }
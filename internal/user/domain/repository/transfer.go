@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type TransferRepository interface {
+	Insert(ctx context.Context, transfer *model.Transfer) (int64, error)
+
+	Sent(ctx context.Context, senderID int64, since time.Time) (types.Decimal, int, error)
+}
@@ -12,5 +12,9 @@ type UserRepository interface {
 
 	LoginExists(ctx context.Context, login string) (bool, error)
 
+	Exists(ctx context.Context, userID int64) (bool, error)
+
+	Lock(ctx context.Context, userID int64) error
+
 	Insert(ctx context.Context, user *model.User) (int64, error)
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type WithdrawalRuleRepository interface {
+	GetAll(ctx context.Context) ([]*model.WithdrawalRuleOverride, error)
+
+	// GetForUser returns the override of the user tier followed by the override of the user, missing ones are skipped.
+	GetForUser(ctx context.Context, userID int64) ([]*model.WithdrawalRuleOverride, error)
+
+	Save(ctx context.Context, override *model.WithdrawalRuleOverride) (int64, error)
+
+	Delete(ctx context.Context, id int64) (bool, error)
+
+	Usage(ctx context.Context, userID int64, dayStart, monthStart time.Time) (*model.WithdrawalUsage, error)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type TierService interface {
+	Progress(ctx context.Context) (*model.TierProgress, error)
+
+	History(ctx context.Context) ([]*model.TierHistory, error)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type TransactionService interface {
+	Reverse(ctx context.Context, id int64, reason model.ReasonCode) (*model.Transaction, error)
+
+	ReverseOrder(ctx context.Context, orderID model.OrderID, tt model.TransactionType, reason model.ReasonCode) (*model.Transaction, error)
+}
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type TransferService interface {
+	Transfer(ctx context.Context, login string, amount types.Decimal, note string) (*model.Transfer, error)
+}
@@ -10,6 +10,13 @@ type UnitOfWork interface {
 	OrderRepository() repository.OrderRepository
 	BonusBalanceRepository() repository.BonusBalanceRepository
 	BonusMovementRepository() repository.TransactionRepository
+	LotRepository() repository.LotRepository
+	TierRepository() repository.TierRepository
+	TransferRepository() repository.TransferRepository
+	PromoCodeRepository() repository.PromoCodeRepository
+	ExportRepository() repository.ExportRepository
+	WithdrawalRuleRepository() repository.WithdrawalRuleRepository
+	QuarantineRepository() repository.QuarantineRepository
 
 	BeginTx(ctx context.Context, fn func(ctx context.Context, uow UnitOfWork) error) error
 }
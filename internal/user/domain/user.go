@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
 )
 
 type UserService interface {
@@ -12,5 +13,11 @@ type UserService interface {
 
 	Balance(ctx context.Context) (*model.BonusBalance, error)
 
+	BalanceAt(ctx context.Context, at time.Time) (*model.BonusBalance, error)
+
 	Withdrawal(ctx context.Context) ([]*model.Transaction, error)
+
+	Transactions(ctx context.Context) ([]*model.Transaction, error)
+
+	Statement(ctx context.Context, from, to time.Time, cursor *model.StatementCursor, limit int) (*model.Statement, error)
 }
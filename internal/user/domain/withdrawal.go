@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+)
+
+type WithdrawalPolicy interface {
+	// Check returns a *model.PolicyViolation when the withdrawal breaks the rules effective for the user.
+	Check(ctx context.Context, userID int64, sum types.Decimal, orderTotal *types.Decimal) error
+
+	// Rules returns the rules effective for the current user.
+	Rules(ctx context.Context) (model.WithdrawalRules, error)
+
+	Overrides(ctx context.Context) ([]*model.WithdrawalRuleOverride, error)
+
+	SaveOverride(ctx context.Context, override *model.WithdrawalRuleOverride) (*model.WithdrawalRuleOverride, error)
+
+	DeleteOverride(ctx context.Context, id int64) error
+}
@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/tripper"
 	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"go.uber.org/zap"
 	"io"
 	"net/http"
+	"regexp"
+	"strconv"
 	"time"
 )
 
@@ -15,6 +20,20 @@ var ErrNoContent = NoContentError{
 	Message: "Order not found",
 }
 
+// ErrMalformedResponse wraps responses that can not be decoded.
+var ErrMalformedResponse = errors.New("malformed accrual response")
+
+// ErrUnavailable means the order was not checked because the circuit breaker holds requests back.
+var ErrUnavailable = errors.New("accrual service is unavailable")
+
+// defaultTimeout bounds an accrual request including retries when the provider sets no timeout of its own.
+const defaultTimeout = 30 * time.Second
+
+// defaultRetryAfter is used when a 429 response carries no usable Retry-After header.
+const defaultRetryAfter = 60 * time.Second
+
+var requestsPerMinuteRe = regexp.MustCompile(`(\d+) requests per minute`)
+
 type AccrualClient interface {
 	Order(ctx context.Context, number string) (*dto.Order, error)
 }
@@ -22,6 +41,7 @@ type AccrualClient interface {
 type accrualClient struct {
 	addr       string
 	httpClient *http.Client
+	limiter    *tripper.RateLimiter
 }
 
 func (a accrualClient) Order(ctx context.Context, number string) (*dto.Order, error) {
@@ -33,6 +53,12 @@ func (a accrualClient) Order(ctx context.Context, number string) (*dto.Order, er
 	req.Header.Add("Content-Type", "application/json")
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
+		if errors.Is(err, tripper.ErrPaused) {
+			return nil, &TooManyRequestsError{Until: a.limiter.PausedUntil()}
+		}
+		if errors.Is(err, tripper.ErrCircuitOpen) {
+			return nil, ErrUnavailable
+		}
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -40,7 +66,10 @@ func (a accrualClient) Order(ctx context.Context, number string) (*dto.Order, er
 		if resp.StatusCode == http.StatusNoContent {
 			return nil, ErrNoContent
 		}
-		return nil, errors.New(resp.Status)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, a.throttle(resp)
+		}
+		return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status}
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -49,12 +78,42 @@ func (a accrualClient) Order(ctx context.Context, number string) (*dto.Order, er
 	var order dto.Order
 	err = json.Unmarshal(body, &order)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrMalformedResponse, err)
 	}
 	return &order, nil
 }
 
-func New(addr string, transportFactories []func(transport http.RoundTripper) http.RoundTripper) AccrualClient {
+// throttle pauses every accrual request until Retry-After and adapts the rate to the limit announced in the body.
+func (a accrualClient) throttle(resp *http.Response) error {
+	until := time.Now().Add(retryAfter(resp.Header.Get("Retry-After")))
+	a.limiter.Pause(until)
+	logger := logging.Logger(resp.Request.Context()).With(zap.Time("until", until))
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if m := requestsPerMinuteRe.FindSubmatch(body); m != nil {
+		if limit, err := strconv.Atoi(string(m[1])); err == nil && limit > 0 {
+			a.limiter.SetRate(float64(limit) / 60)
+			logger = logger.With(zap.Int("requests_per_minute", limit))
+		}
+	}
+	logger.Warn("accrual service rate limit reached, pausing requests")
+	return &TooManyRequestsError{Until: until}
+}
+
+func retryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return defaultRetryAfter
+}
+
+// New creates a client of the accrual service at addr, a zero timeout falls back to 30 seconds.
+func New(addr string, timeout time.Duration, limiter *tripper.RateLimiter, transportFactories []func(transport http.RoundTripper) http.RoundTripper) AccrualClient {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
 	var transport http.RoundTripper
 	defaultTransport := &http.Transport{}
 	transport = defaultTransport
@@ -64,9 +123,10 @@ func New(addr string, transportFactories []func(transport http.RoundTripper) htt
 	return &accrualClient{
 		httpClient: &http.Client{
 			Transport: transport,
-			Timeout:   30 * time.Second,
+			Timeout:   timeout,
 		},
-		addr: addr,
+		addr:    addr,
+		limiter: limiter,
 	}
 }
 
@@ -77,3 +137,22 @@ type NoContentError struct {
 func (e NoContentError) Error() string {
 	return e.Message
 }
+
+// TooManyRequestsError means the order was not checked because accrual requests are paused until Until.
+type TooManyRequestsError struct {
+	Until time.Time
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("accrual requests are paused until %s", e.Until.Format(time.RFC3339))
+}
+
+// StatusError is an unexpected response status of the accrual service.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status
+}
@@ -18,7 +18,7 @@ func (s OrderStatus) String() string {
 }
 
 type Order struct {
-	Number  string
-	Status  OrderStatus
-	Accrual *types.Decimal
+	Number  string         `json:"order"`
+	Status  OrderStatus    `json:"status"`
+	Accrual *types.Decimal `json:"accrual,omitempty"`
 }
@@ -0,0 +1,73 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/infrastructure/external/accrual/dto"
+	"sort"
+)
+
+// providerMetrics counts order checks per provider and outcome, they are published with the other expvar metrics.
+var providerMetrics = expvar.NewMap("accrual_providers")
+
+// Providers holds the clients of the named accrual services.
+type Providers struct {
+	clients map[string]AccrualClient
+	names   []string
+}
+
+// NewProviders wraps every client to count its requests, the model.DefaultProvider client is required
+// because it checks the orders without a known provider.
+func NewProviders(clients map[string]AccrualClient) (*Providers, error) {
+	if _, ok := clients[model.DefaultProvider]; !ok {
+		return nil, fmt.Errorf("accrual provider %q is not configured", model.DefaultProvider)
+	}
+	providers := &Providers{clients: make(map[string]AccrualClient, len(clients))}
+	for name, client := range clients {
+		metrics := new(expvar.Map).Init()
+		providerMetrics.Set(name, metrics)
+		providers.clients[name] = &meteredClient{AccrualClient: client, metrics: metrics}
+		providers.names = append(providers.names, name)
+	}
+	sort.Strings(providers.names)
+	return providers, nil
+}
+
+// Client returns the client of the provider, unknown providers fall back to model.DefaultProvider.
+func (p *Providers) Client(provider string) (string, AccrualClient) {
+	if client, ok := p.clients[provider]; ok {
+		return provider, client
+	}
+	return model.DefaultProvider, p.clients[model.DefaultProvider]
+}
+
+// Names returns the configured providers in alphabetical order.
+func (p *Providers) Names() []string {
+	return p.names
+}
+
+type meteredClient struct {
+	AccrualClient
+	metrics *expvar.Map
+}
+
+func (m *meteredClient) Order(ctx context.Context, number string) (*dto.Order, error) {
+	order, err := m.AccrualClient.Order(ctx, number)
+	var throttled *TooManyRequestsError
+	switch {
+	case err == nil:
+		m.metrics.Add(string(order.Status), 1)
+	case errors.Is(err, ErrNoContent):
+		m.metrics.Add("not_registered", 1)
+	case errors.As(err, &throttled):
+		m.metrics.Add("throttled", 1)
+	case errors.Is(err, ErrUnavailable):
+		m.metrics.Add("unavailable", 1)
+	default:
+		m.metrics.Add("failed", 1)
+	}
+	return order, err
+}
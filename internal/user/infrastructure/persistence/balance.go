@@ -3,13 +3,42 @@ package persistence
 import (
 	"context"
 	"github.com/DimKa163/gophermart/internal/shared/db"
-	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
 )
 
+// balance columns follow the definition of the bonus_balances view.
 const (
-	balanceGetSQL = `SELECT user_id, current, accrued, withdrawn FROM bonus_balances WHERE user_id = $1`
+	accruedSumSQL   = `SUM(CASE WHEN t.type IN (0, 5) THEN t.amount WHEN t.type = 3 THEN -t.amount ELSE 0 END)`
+	withdrawnSumSQL = `SUM(CASE WHEN t.type = 1 THEN t.amount WHEN t.type = 2 THEN -t.amount ELSE 0 END)`
+	currentSumSQL   = `SUM(CASE WHEN t.type IN (0, 2, 5, 7, 8) THEN t.amount WHEN t.type IN (1, 3, 4, 6) THEN -t.amount ELSE 0 END)`
+	expiredSumSQL   = `SUM(CASE WHEN t.type = 4 THEN t.amount ELSE 0 END)`
+)
+
+const (
+	balanceGetSQL   = `SELECT user_id, current, accrued, withdrawn FROM bonus_balances WHERE user_id = $1`
+	balanceGetAtSQL = `WITH s AS (SELECT taken_at, accrued, withdrawn, current FROM balance_snapshots
+							WHERE user_id = $1 AND taken_at <= $2 ORDER BY taken_at DESC LIMIT 1)
+						SELECT COALESCE((SELECT current FROM s), 0) + COALESCE(` + currentSumSQL + `, 0),
+							COALESCE((SELECT accrued FROM s), 0) + COALESCE(` + accruedSumSQL + `, 0),
+							COALESCE((SELECT withdrawn FROM s), 0) + COALESCE(` + withdrawnSumSQL + `, 0)
+						FROM transactions t
+						WHERE t.user_id = $1 AND t.created_at <= $2
+							AND t.created_at >= COALESCE((SELECT taken_at FROM s), '-infinity')`
+	balanceSnapshotSQL = `INSERT INTO balance_snapshots (user_id, taken_at, accrued, withdrawn, current, expired)
+						SELECT t.user_id, $1,
+							COALESCE(s.accrued, 0) + ` + accruedSumSQL + `,
+							COALESCE(s.withdrawn, 0) + ` + withdrawnSumSQL + `,
+							COALESCE(s.current, 0) + ` + currentSumSQL + `,
+							COALESCE(s.expired, 0) + ` + expiredSumSQL + `
+						FROM transactions t
+						LEFT JOIN LATERAL (SELECT taken_at, accrued, withdrawn, current, expired FROM balance_snapshots bs
+							WHERE bs.user_id = t.user_id ORDER BY bs.taken_at DESC LIMIT 1) s ON TRUE
+						WHERE t.created_at < $1 AND t.created_at >= COALESCE(s.taken_at, '-infinity')
+						GROUP BY t.user_id, s.accrued, s.withdrawn, s.current, s.expired
+						ON CONFLICT (user_id, taken_at) DO NOTHING`
 )
 
 type bonusBalanceRepository struct {
@@ -19,26 +48,30 @@ type bonusBalanceRepository struct {
 
 func (b *bonusBalanceRepository) Get(ctx context.Context, userID int64) (*model.BonusBalance, error) {
 	var balance model.BonusBalance
-	var err error
-	var currentStr string
-	var accrued string
-	var withdrawnStr string
-	if err = b.QueryRowWithRetry(ctx, b.db, balanceGetSQL, []any{userID}, &balance.UserID, &currentStr, &accrued, &withdrawnStr); err != nil {
-		return nil, err
-	}
-	balance.Current, err = types.NewDecimalFromString(currentStr)
-	if err != nil {
+	if err := b.QueryRowWithRetry(ctx, b.db, balanceGetSQL, []any{userID},
+		&balance.UserID, &balance.Current, &balance.Accrued, &balance.Withdrawn); err != nil {
 		return nil, err
 	}
-	balance.Accrued, err = types.NewDecimalFromString(accrued)
-	if err != nil {
+	return &balance, nil
+}
+
+func (b *bonusBalanceRepository) GetAt(ctx context.Context, userID int64, at time.Time) (*model.BonusBalance, error) {
+	balance := model.BonusBalance{UserID: userID}
+	if err := b.QueryRowWithRetry(ctx, b.db, balanceGetAtSQL, []any{userID, at},
+		&balance.Current, &balance.Accrued, &balance.Withdrawn); err != nil {
 		return nil, err
 	}
-	balance.Withdrawn, err = types.NewDecimalFromString(withdrawnStr)
+	return &balance, nil
+}
+
+func (b *bonusBalanceRepository) Snapshot(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := b.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return b.db.Exec(ctx, balanceSnapshotSQL, cutoff)
+	})
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return &balance, nil
+	return tag.RowsAffected(), nil
 }
 
 func NewBonusBalanceRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.BonusBalanceRepository {
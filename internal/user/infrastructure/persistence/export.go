@@ -0,0 +1,169 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	exportColumns = `id, created_at, user_id, format, period_from, period_to, types, status, total, done, file, error,
+						completed_at, expires_at`
+	insertExportSQL = `INSERT INTO exports (created_at, user_id, format, period_from, period_to, types, status)
+						VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+	getExportSQL   = `SELECT ` + exportColumns + ` FROM exports WHERE id = $1 AND user_id = $2`
+	claimExportSQL = `UPDATE exports SET status = 1, claimed_at = $1, done = 0
+						WHERE id = (SELECT id FROM exports WHERE status = 0 OR (status = 1 AND claimed_at < $2)
+							ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED)
+						RETURNING ` + exportColumns
+	updateExportProgressSQL = `UPDATE exports SET total = $1, done = $2 WHERE id = $3`
+	updateExportSQL         = `UPDATE exports SET status = $1, total = $2, done = $3, file = $4, error = $5,
+								completed_at = $6, expires_at = $7 WHERE id = $8`
+	getExpiredExportsSQL = `SELECT ` + exportColumns + ` FROM exports WHERE status = 2 AND expires_at <= $1 ORDER BY id`
+)
+
+type exportRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (e *exportRepository) Insert(ctx context.Context, export *model.Export) (int64, error) {
+	if err := e.QueryRowWithRetry(ctx, e.db, insertExportSQL, []any{
+		time.Now(),
+		export.UserID,
+		export.Format,
+		export.From,
+		export.To,
+		transactionTypes(export.Types),
+		export.Status,
+	}, &export.ID, &export.CreatedAt); err != nil {
+		return -1, err
+	}
+	return export.ID, nil
+}
+
+func (e *exportRepository) Get(ctx context.Context, id, userID int64) (*model.Export, error) {
+	return e.queryRow(ctx, getExportSQL, id, userID)
+}
+
+func (e *exportRepository) Claim(ctx context.Context, staleBefore time.Time) (*model.Export, error) {
+	return e.queryRow(ctx, claimExportSQL, time.Now(), staleBefore)
+}
+
+func (e *exportRepository) Progress(ctx context.Context, export *model.Export) error {
+	_, err := e.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return e.db.Exec(ctx, updateExportProgressSQL, export.Total, export.Done, export.ID)
+	})
+	return err
+}
+
+func (e *exportRepository) Update(ctx context.Context, export *model.Export) error {
+	var file *string
+	if export.File != "" {
+		file = &export.File
+	}
+	var exportErr *string
+	if export.Error != "" {
+		exportErr = &export.Error
+	}
+	_, err := e.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return e.db.Exec(ctx, updateExportSQL,
+			export.Status,
+			export.Total,
+			export.Done,
+			file,
+			exportErr,
+			export.CompletedAt,
+			export.ExpiresAt,
+			export.ID)
+	})
+	return err
+}
+
+func (e *exportRepository) GetExpired(ctx context.Context, at time.Time) ([]*model.Export, error) {
+	rows, err := e.QueryWithRetry(ctx, e.db, getExpiredExportsSQL, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var exports []*model.Export
+	for rows.Next() {
+		export, err := scanExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, rows.Err()
+}
+
+func (e *exportRepository) queryRow(ctx context.Context, sql string, args ...any) (*model.Export, error) {
+	rows, err := e.QueryWithRetry(ctx, e.db, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanExport(rows)
+}
+
+// transactionTypes converts the filter to a smallint array, no types is stored as NULL.
+func transactionTypes(tt []model.TransactionType) []int16 {
+	if len(tt) == 0 {
+		return nil
+	}
+	values := make([]int16, len(tt))
+	for i, t := range tt {
+		values[i] = int16(t)
+	}
+	return values
+}
+
+func scanExport(rows pgx.Rows) (*model.Export, error) {
+	var export model.Export
+	var types []int16
+	var file *string
+	var exportErr *string
+	if err := rows.Scan(&export.ID,
+		&export.CreatedAt,
+		&export.UserID,
+		&export.Format,
+		&export.From,
+		&export.To,
+		&types,
+		&export.Status,
+		&export.Total,
+		&export.Done,
+		&file,
+		&exportErr,
+		&export.CompletedAt,
+		&export.ExpiresAt); err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		export.Types = append(export.Types, model.TransactionType(t))
+	}
+	if file != nil {
+		export.File = *file
+	}
+	if exportErr != nil {
+		export.Error = *exportErr
+	}
+	return &export, nil
+}
+
+func NewExportRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.ExportRepository {
+	return &exportRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
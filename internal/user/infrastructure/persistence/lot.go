@@ -0,0 +1,185 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	insertLotSQL = `INSERT INTO point_lots (created_at, user_id, transaction_id, amount, remaining)
+						VALUES ($1, $2, $3, $4, $4)`
+	selectLotsForConsumeSQL = `SELECT l.id, l.user_id, l.transaction_id, t.order_id, l.created_at, l.amount, l.remaining
+									FROM point_lots l JOIN transactions t ON t.id = l.transaction_id
+									WHERE l.user_id = $1 AND l.remaining > 0
+									ORDER BY l.created_at, l.id FOR UPDATE OF l`
+	selectTransactionLotForUpdateSQL = `SELECT l.id, l.user_id, l.transaction_id, t.order_id, l.created_at, l.amount, l.remaining
+									FROM point_lots l JOIN transactions t ON t.id = l.transaction_id
+									WHERE l.transaction_id = $1 AND l.remaining > 0 FOR UPDATE OF l`
+	selectExpiredLotsSQL = `SELECT l.id, l.user_id, l.transaction_id, t.order_id, l.created_at, l.amount, l.remaining
+									FROM point_lots l JOIN transactions t ON t.id = l.transaction_id
+									WHERE l.remaining > 0 AND l.created_at <= $1
+									ORDER BY l.created_at, l.id LIMIT $2 OFFSET $3 FOR UPDATE OF l SKIP LOCKED`
+	selectExpiredOwnersSQL = `SELECT DISTINCT user_id FROM point_lots
+									WHERE remaining > 0 AND created_at <= $1
+									ORDER BY user_id LIMIT $2`
+	selectExpiredUserLotsSQL = `SELECT l.id, l.user_id, l.transaction_id, t.order_id, l.created_at, l.amount, l.remaining
+									FROM point_lots l JOIN transactions t ON t.id = l.transaction_id
+									WHERE l.user_id = $1 AND l.remaining > 0 AND l.created_at <= $2
+									ORDER BY l.created_at, l.id FOR UPDATE OF l`
+	selectExpiringLotsSQL = `SELECT l.id, l.user_id, l.transaction_id, t.order_id, l.created_at, l.amount, l.remaining
+									FROM point_lots l JOIN transactions t ON t.id = l.transaction_id
+									WHERE l.user_id = $1 AND l.remaining > 0 AND l.created_at <= $2
+									ORDER BY l.created_at, l.id`
+	updateLotRemainingSQL = `UPDATE point_lots SET remaining = $1 WHERE id = $2`
+)
+
+type lotRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (l *lotRepository) GetExpired(ctx context.Context, cutoff time.Time, limit, offset int) ([]*model.Lot, error) {
+	return l.query(ctx, selectExpiredLotsSQL, cutoff, limit, offset)
+}
+
+func (l *lotRepository) GetExpiredOwners(ctx context.Context, cutoff time.Time, limit int) ([]int64, error) {
+	rows, err := l.QueryWithRetry(ctx, l.db, selectExpiredOwnersSQL, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var owners []int64
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		owners = append(owners, userID)
+	}
+	return owners, rows.Err()
+}
+
+func (l *lotRepository) GetExpiredForUpdate(ctx context.Context, userID int64, cutoff time.Time) ([]*model.Lot, error) {
+	return l.query(ctx, selectExpiredUserLotsSQL, userID, cutoff)
+}
+
+func (l *lotRepository) GetExpiring(ctx context.Context, userID int64, cutoff time.Time) ([]*model.Lot, error) {
+	return l.query(ctx, selectExpiringLotsSQL, userID, cutoff)
+}
+
+func (l *lotRepository) Expire(ctx context.Context, lot *model.Lot) error {
+	lot.Remaining = types.Decimal{}
+	return l.updateRemaining(ctx, lot)
+}
+
+// add opens a lot for a credit transaction.
+func (l *lotRepository) add(ctx context.Context, tr *model.Transaction) error {
+	_, err := l.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return l.db.Exec(ctx, insertLotSQL, time.Now(), tr.UserID, tr.ID, tr.Amount)
+	})
+	return err
+}
+
+// consume spends the debit from the oldest lots of the user. A reversal is taken out of the lot opened by
+// the reversed transaction first, otherwise the clawed back points would stay in it and expire once more.
+func (l *lotRepository) consume(ctx context.Context, tr *model.Transaction) error {
+	rest := tr.Amount
+	if tr.Type == model.REVERSAL && tr.ReferenceID != nil {
+		lots, err := l.query(ctx, selectTransactionLotForUpdateSQL, *tr.ReferenceID)
+		if err != nil {
+			return err
+		}
+		if rest, err = l.take(ctx, lots, rest); err != nil {
+			return err
+		}
+	}
+	if rest.IsPositive() {
+		lots, err := l.query(ctx, selectLotsForConsumeSQL, tr.UserID)
+		if err != nil {
+			return err
+		}
+		if rest, err = l.take(ctx, lots, rest); err != nil {
+			return err
+		}
+	}
+	if rest.IsPositive() {
+		return fmt.Errorf("%w: %s left for user %d", model.ErrLotsExhausted, rest.String(), tr.UserID)
+	}
+	return nil
+}
+
+// take spends up to amount from the lots in order and returns the part they did not cover.
+func (l *lotRepository) take(ctx context.Context, lots []*model.Lot, amount types.Decimal) (types.Decimal, error) {
+	rest := amount
+	for _, lot := range lots {
+		if !rest.IsPositive() {
+			break
+		}
+		take := lot.Remaining
+		if take.Cmp(rest) > 0 {
+			take = rest
+		}
+		lot.Remaining = lot.Remaining.Sub(take)
+		rest = rest.Sub(take)
+		if err := l.updateRemaining(ctx, lot); err != nil {
+			return rest, err
+		}
+	}
+	return rest, nil
+}
+
+func (l *lotRepository) updateRemaining(ctx context.Context, lot *model.Lot) error {
+	_, err := l.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return l.db.Exec(ctx, updateLotRemainingSQL, lot.Remaining, lot.ID)
+	})
+	return err
+}
+
+func (l *lotRepository) query(ctx context.Context, sql string, args ...any) ([]*model.Lot, error) {
+	rows, err := l.QueryWithRetry(ctx, l.db, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var lots []*model.Lot
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, lot)
+	}
+	return lots, rows.Err()
+}
+
+func scanLot(rows pgx.Rows) (*model.Lot, error) {
+	var lot model.Lot
+	var orderID *int64
+	if err := rows.Scan(&lot.ID,
+		&lot.UserID,
+		&lot.TransactionID,
+		&orderID,
+		&lot.CreatedAt,
+		&lot.Amount,
+		&lot.Remaining); err != nil {
+		return nil, err
+	}
+	if orderID != nil {
+		lot.OrderID = model.OrderID{Value: *orderID}
+	}
+	return &lot, nil
+}
+
+func NewLotRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.LotRepository {
+	return &lotRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
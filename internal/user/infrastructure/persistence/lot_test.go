@@ -0,0 +1,82 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func testUser(t *testing.T, unitOfWork uow.UnitOfWork) int64 {
+	now := time.Now()
+	userID, err := unitOfWork.UserRepository().Insert(context.Background(), &model.User{
+		CreatedAt: now,
+		Login:     fmt.Sprintf("lots-%d", now.UnixNano()%1_000_000_000),
+		Password:  []byte("password"),
+		Salt:      []byte("salt"),
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return userID
+}
+
+func lotRemaining(t *testing.T, pool *pgxpool.Pool, transactionID int64) types.Decimal {
+	var remaining types.Decimal
+	assert.NoError(t, pool.QueryRow(context.Background(),
+		`SELECT remaining FROM point_lots WHERE transaction_id = $1`, transactionID).Scan(&remaining))
+	return remaining
+}
+
+func TestReversalShouldConsumeTheLotOfTheReversedTransaction(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	unitOfWork := NewUnitOfWork(pool, db.NewRetryStrategy([]int{1, 3, 5}))
+	userID := testUser(t, unitOfWork)
+	rep := unitOfWork.BonusMovementRepository()
+	older, err := rep.Insert(ctx, &model.Transaction{UserID: userID, Type: model.ACCRUAL,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(100)}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	newer, err := rep.Insert(ctx, &model.Transaction{UserID: userID, Type: model.ACCRUAL,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(50)}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = rep.Insert(ctx, &model.Transaction{UserID: userID, Type: model.REVERSAL, ReferenceID: &newer,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(50)}, Reason: model.ReasonAccrualCorrected})
+
+	assert.NoError(t, err)
+	assert.True(t, lotRemaining(t, pool, older).Equal(decimal.NewFromInt(100)), "the oldest lot should be kept")
+	assert.True(t, lotRemaining(t, pool, newer).IsZero(), "the reversed lot should be closed")
+}
+
+func TestDebitNotCoveredByLotsShouldReturnError(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	unitOfWork := NewUnitOfWork(pool, db.NewRetryStrategy([]int{1, 3, 5}))
+	userID := testUser(t, unitOfWork)
+	rep := unitOfWork.BonusMovementRepository()
+	_, err := rep.Insert(ctx, &model.Transaction{UserID: userID, Type: model.ACCRUAL,
+		Amount: types.Decimal{Decimal: decimal.NewFromInt(100)}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	err = unitOfWork.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+		_, err := uow.BonusMovementRepository().Insert(ctx, &model.Transaction{UserID: userID, Type: model.WITHDRAWAL,
+			Amount: types.Decimal{Decimal: decimal.NewFromInt(150)}})
+		return err
+	})
+
+	assert.ErrorIs(t, err, model.ErrLotsExhausted)
+}
@@ -9,7 +9,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func Migrate(pgx *pgxpool.Pool) error {
+// Migrate applies the migrations found at source, e.g. "file://migrations" relative to the working directory.
+func Migrate(pgx *pgxpool.Pool, source string) error {
 	var err error
 	db, err := sql.Open("postgres", pgx.Config().ConnString())
 	if err != nil {
@@ -19,7 +20,7 @@ func Migrate(pgx *pgxpool.Pool) error {
 	if err != nil {
 		return err
 	}
-	m, err := migrate.NewWithDatabaseInstance("file://migrations", "postgres", driver)
+	m, err := migrate.NewWithDatabaseInstance(source, "postgres", driver)
 	if err != nil {
 		return err
 	}
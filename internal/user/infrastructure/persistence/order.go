@@ -6,25 +6,52 @@ import (
 	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"time"
 )
 
 const (
-	orderExistsSQL       = "SELECT COUNT(*) FROM orders WHERE id = $1"
-	updateOrderSQL       = "UPDATE orders SET status=$1, accrual=$2 WHERE id=$3"
-	insertTransactionSQL = `INSERT INTO transactions (created_at, user_id, type, amount, order_id) 
-								VALUES ($1, $2, $3, $4, $5)`
-	selectOrderForUpdateSQL = `SELECT id, uploaded_at, user_id, status, accrual 
-									FROM orders WHERE status=ANY($1) ORDER BY uploaded_at 
-									LIMIT $2 OFFSET $3 FOR UPDATE SKIP LOCKED`
-	getOrderSQL = `SELECT id, uploaded_at, user_id, status, accrual FROM orders WHERE id=$1`
-
-	getAllOrdersSQL = `SELECT id, uploaded_at, user_id, status, accrual FROM orders WHERE user_id=$1`
-
-	insertOrderSQL = `INSERT INTO orders (id, uploaded_at, user_id, status) VALUES ($1, $2, $3, $4) RETURNING id`
+	orderExistsSQL = "SELECT COUNT(*) FROM orders WHERE id = $1"
+	updateOrderSQL = "UPDATE orders SET status=$1, accrual=$2, next_check_at=$3, attempts=$4 WHERE id=$5"
+	// claimOrdersSQL leases due orders that are free or whose lease ran out, so orders of a crashed instance come back
+	claimOrdersSQL = `UPDATE orders SET leased_by = $4, leased_until = $5
+									WHERE id IN (SELECT id FROM orders
+										WHERE status = ANY($1) AND next_check_at <= $2
+											AND dead_lettered_at IS NULL
+											AND (leased_until IS NULL OR leased_until < $2)
+										ORDER BY next_check_at LIMIT $3 FOR UPDATE SKIP LOCKED)
+									RETURNING id, uploaded_at, user_id, status, accrual, next_check_at, attempts, last_error, error_class, dead_lettered_at, provider`
+	updateLeasedOrderSQL = `UPDATE orders SET status = $1, accrual = $2, next_check_at = $3, attempts = $4,
+									last_error = $5, error_class = $6, dead_lettered_at = $7,
+									leased_by = NULL, leased_until = NULL
+									WHERE id = $8 AND leased_by = $9 AND leased_until >= $10`
+	// updateDeadLetteredSQL only touches orders still dead-lettered, so an order is requeued or resolved once
+	updateDeadLetteredSQL = `UPDATE orders SET status = $1, accrual = $2, next_check_at = $3, attempts = $4,
+									last_error = $5, error_class = $6, dead_lettered_at = $7
+									WHERE id = $8 AND dead_lettered_at IS NOT NULL`
+	getDeadLetteredSQL = `SELECT id, uploaded_at, user_id, status, accrual, next_check_at, attempts, last_error, error_class, dead_lettered_at, provider FROM orders
+									WHERE dead_lettered_at IS NOT NULL ORDER BY dead_lettered_at DESC, id LIMIT $1 OFFSET $2`
+	releaseOrdersSQL     = `UPDATE orders SET leased_by = NULL, leased_until = NULL WHERE id = ANY($1) AND leased_by = $2`
+	getOrderSQL          = `SELECT id, uploaded_at, user_id, status, accrual, next_check_at, attempts, last_error, error_class, dead_lettered_at, provider FROM orders WHERE id=$1`
+	getOrderForUpdateSQL = `SELECT id, uploaded_at, user_id, status, accrual, next_check_at, attempts, last_error, error_class, dead_lettered_at, provider
+									FROM orders WHERE id=$1 FOR UPDATE`
+	// updateCheckedOrderSQL drops the lease, so a check of the same order running elsewhere can not save over it
+	updateCheckedOrderSQL = `UPDATE orders SET status = $1, accrual = $2, next_check_at = $3, attempts = $4,
+									last_error = $5, error_class = $6, dead_lettered_at = $7,
+									leased_by = NULL, leased_until = NULL
+									WHERE id = $8`
+
+	getAllOrdersSQL = `SELECT id, uploaded_at, user_id, status, accrual, next_check_at, attempts, last_error, error_class, dead_lettered_at, provider FROM orders WHERE user_id=$1`
+
+	insertOrderSQL = `INSERT INTO orders (id, uploaded_at, user_id, status, provider) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	notifyOrderUploadedSQL = `SELECT pg_notify($1, $2)`
 )
 
+// OrderUploadedChannel is the notification channel carrying the numbers of uploaded orders.
+const OrderUploadedChannel = "order_uploaded"
+
 type orderRepository struct {
 	db db.QueryExecutor
 	*db.RetryStrategy
@@ -40,7 +67,7 @@ func (o *orderRepository) Exists(ctx context.Context, id model.OrderID) (bool, e
 
 func (o *orderRepository) Update(ctx context.Context, order *model.Order) error {
 	if _, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
-		tg, err := o.db.Exec(ctx, updateOrderSQL, order.Status, &order.Accrual, order.OrderID.Value)
+		tg, err := o.db.Exec(ctx, updateOrderSQL, order.Status, &order.Accrual, order.NextCheckAt, order.Attempts, order.OrderID.Value)
 		if err != nil {
 			return pgconn.CommandTag{}, err
 		}
@@ -50,71 +77,90 @@ func (o *orderRepository) Update(ctx context.Context, order *model.Order) error
 	}
 
 	for _, tr := range order.Transactions() {
-		if _, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
-			tg, err := o.db.Exec(ctx, insertTransactionSQL, time.Now(), tr.UserID, tr.Type, tr.Amount, tr.OrderID.Value)
-			if err != nil {
-				return pgconn.CommandTag{}, err
-			}
-			return tg, nil
-		}); err != nil {
+		if _, err := insertTransaction(ctx, o.db, o.RetryStrategy, tr); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (o *orderRepository) GetForUpdate(ctx context.Context, limit, offset int, status ...model.OrderStatus) ([]*model.Order, error) {
+func (o *orderRepository) Claim(ctx context.Context, lease model.Lease, limit int, status ...model.OrderStatus) ([]*model.Order, error) {
 	var orders []*model.Order
-	rows, err := o.QueryWithRetry(ctx, o.db, selectOrderForUpdateSQL, status, limit, offset)
+	rows, err := o.QueryWithRetry(ctx, o.db, claimOrdersSQL, status, lease.At, limit, lease.Owner, lease.Until)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var orderID int64
-		var order model.Order
-		var accrual *string
-		if err := rows.Scan(&orderID, &order.UploadedAt, &order.UserID, &order.Status, &accrual); err != nil {
+		order, err := scanOrder(rows)
+		if err != nil {
 			return nil, err
 		}
-		order.OrderID = model.OrderID{Value: orderID}
-		var acc types.Decimal
-		if accrual != nil {
-			acc, err = types.NewDecimalFromString(*accrual)
-			if err != nil {
-				return nil, err
-			}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (o *orderRepository) UpdateLeased(ctx context.Context, order *model.Order, lease model.Lease) error {
+	tag, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return o.db.Exec(ctx, updateLeasedOrderSQL, order.Status, &order.Accrual, order.NextCheckAt, order.Attempts,
+			nullString(order.Error), nullString(string(order.ErrorClass)), order.DeadLetteredAt,
+			order.OrderID.Value, lease.Owner, lease.At)
+	})
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrLeaseLost
+	}
+	for _, tr := range order.Transactions() {
+		if _, err := insertTransaction(ctx, o.db, o.RetryStrategy, tr); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		order.Accrual = acc
-		orders = append(orders, &order)
+func (o *orderRepository) Release(ctx context.Context, ids []model.OrderID, owner string) error {
+	values := make([]int64, len(ids))
+	for i, id := range ids {
+		values[i] = id.Value
 	}
-	return orders, nil
+	_, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return o.db.Exec(ctx, releaseOrdersSQL, values, owner)
+	})
+	return err
 }
 
 func (o *orderRepository) Get(ctx context.Context, id model.OrderID) (*model.Order, error) {
-	var order model.Order
-	var orderID int64
-	var accrual *string
-	if err := o.QueryRowWithRetry(ctx, o.db, getOrderSQL, []any{id.Value},
-		&orderID,
-		&order.UploadedAt,
-		&order.UserID,
-		&order.Status,
-		&accrual); err != nil {
+	var row orderRow
+	if err := o.QueryRowWithRetry(ctx, o.db, getOrderSQL, []any{id.Value}, row.dest()...); err != nil {
 		return nil, err
 	}
-	var acc types.Decimal
-	var err error
-	if accrual != nil {
-		acc, err = types.NewDecimalFromString(*accrual)
-		if err != nil {
-			return nil, err
+	return row.order(), nil
+}
+
+func (o *orderRepository) GetForUpdate(ctx context.Context, id model.OrderID) (*model.Order, error) {
+	var row orderRow
+	if err := o.QueryRowWithRetry(ctx, o.db, getOrderForUpdateSQL, []any{id.Value}, row.dest()...); err != nil {
+		return nil, err
+	}
+	return row.order(), nil
+}
+
+func (o *orderRepository) UpdateChecked(ctx context.Context, order *model.Order) error {
+	if _, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return o.db.Exec(ctx, updateCheckedOrderSQL, order.Status, &order.Accrual, order.NextCheckAt, order.Attempts,
+			nullString(order.Error), nullString(string(order.ErrorClass)), order.DeadLetteredAt, order.OrderID.Value)
+	}); err != nil {
+		return err
+	}
+	for _, tr := range order.Transactions() {
+		if _, err := insertTransaction(ctx, o.db, o.RetryStrategy, tr); err != nil {
+			return err
 		}
 	}
-	order.Accrual = acc
-	order.OrderID = model.OrderID{Value: orderID}
-	return &order, nil
+	return nil
 }
 
 func (o *orderRepository) GetAll(ctx context.Context, userID int64) ([]*model.Order, error) {
@@ -125,36 +171,116 @@ func (o *orderRepository) GetAll(ctx context.Context, userID int64) ([]*model.Or
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var orderID int64
-		var order model.Order
-		var accrual *string
-		if err := rows.Scan(&orderID, &order.UploadedAt, &order.UserID, &order.Status, &accrual); err != nil {
+		order, err := scanOrder(rows)
+		if err != nil {
 			return nil, err
 		}
-		order.OrderID = model.OrderID{Value: orderID}
-		var acc types.Decimal
-		if accrual != nil {
-			acc, err = types.NewDecimalFromString(*accrual)
-			if err != nil {
-				return nil, err
-			}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (o *orderRepository) GetDeadLettered(ctx context.Context, limit, offset int) ([]*model.Order, error) {
+	var orders []*model.Order
+	rows, err := o.QueryWithRetry(ctx, o.db, getDeadLetteredSQL, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
 		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
 
-		order.Accrual = acc
-		orders = append(orders, &order)
+func (o *orderRepository) UpdateDeadLettered(ctx context.Context, order *model.Order) error {
+	tag, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return o.db.Exec(ctx, updateDeadLetteredSQL, order.Status, &order.Accrual, order.NextCheckAt, order.Attempts,
+			nullString(order.Error), nullString(string(order.ErrorClass)), order.DeadLetteredAt, order.OrderID.Value)
+	})
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNotDeadLettered
 	}
-	return orders, nil
+	for _, tr := range order.Transactions() {
+		if _, err := insertTransaction(ctx, o.db, o.RetryStrategy, tr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (o *orderRepository) Insert(ctx context.Context, order *model.Order) (model.OrderID, error) {
 	var id string
-	if err := o.QueryRowWithRetry(ctx, o.db, insertOrderSQL, []any{order.OrderID.Value, time.Now(), order.UserID, order.Status}, &id); err != nil {
+	if err := o.QueryRowWithRetry(ctx, o.db, insertOrderSQL, []any{order.OrderID.Value, time.Now(), order.UserID, order.Status, nullString(order.Provider)}, &id); err != nil {
 		return model.DefaultOrderID, err
 	}
 	orderID, _ := model.NewOrderID(id)
 	return orderID, nil
 }
 
+func (o *orderRepository) NotifyUploaded(ctx context.Context, id model.OrderID) error {
+	_, err := o.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return o.db.Exec(ctx, notifyOrderUploadedSQL, OrderUploadedChannel, id.String())
+	})
+	return err
+}
+
+// orderRow holds the scan targets of an order row, the tracking error columns are nullable.
+type orderRow struct {
+	id         int64
+	accrual    *types.Decimal
+	lastError  *string
+	errorClass *string
+	provider   *string
+	value      model.Order
+}
+
+func (r *orderRow) dest() []any {
+	return []any{&r.id, &r.value.UploadedAt, &r.value.UserID, &r.value.Status, &r.accrual,
+		&r.value.NextCheckAt, &r.value.Attempts, &r.lastError, &r.errorClass, &r.value.DeadLetteredAt, &r.provider}
+}
+
+func (r *orderRow) order() *model.Order {
+	order := r.value
+	order.OrderID = model.OrderID{Value: r.id}
+	if r.accrual != nil {
+		order.Accrual = *r.accrual
+	}
+	if r.lastError != nil {
+		order.Error = *r.lastError
+	}
+	if r.errorClass != nil {
+		order.ErrorClass = model.ErrorClass(*r.errorClass)
+	}
+	order.Provider = model.DefaultProvider
+	if r.provider != nil {
+		order.Provider = *r.provider
+	}
+	return &order
+}
+
+func scanOrder(rows pgx.Rows) (*model.Order, error) {
+	var row orderRow
+	if err := rows.Scan(row.dest()...); err != nil {
+		return nil, err
+	}
+	return row.order(), nil
+}
+
+func nullString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 func NewOrderRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.OrderRepository {
 	return &orderRepository{
 		db:            db,
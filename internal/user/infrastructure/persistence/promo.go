@@ -0,0 +1,133 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	promoColumns = `id, created_at, code, amount, max_redemptions, per_user_limit, starts_at, ends_at,
+						single_use, redemptions`
+	promoExistsSQL       = `SELECT EXISTS(SELECT 1 FROM promo_codes WHERE code = $1)`
+	promoGetAllSQL       = `SELECT ` + promoColumns + ` FROM promo_codes ORDER BY id DESC LIMIT $1 OFFSET $2`
+	promoGetForUpdateSQL = `SELECT ` + promoColumns + ` FROM promo_codes WHERE code = $1 FOR UPDATE`
+	insertPromoSQL       = `INSERT INTO promo_codes (created_at, code, amount, max_redemptions, per_user_limit,
+								starts_at, ends_at, single_use) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`
+	promoUserRedemptionsSQL  = `SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2`
+	insertPromoRedemptionSQL = `INSERT INTO promo_redemptions (created_at, promo_code_id, user_id, transaction_id)
+								VALUES ($1, $2, $3, $4)`
+	updatePromoRedemptionsSQL = `UPDATE promo_codes SET redemptions = redemptions + 1 WHERE id = $1`
+)
+
+type promoCodeRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (p *promoCodeRepository) Exists(ctx context.Context, code string) (bool, error) {
+	var exists bool
+	if err := p.QueryRowWithRetry(ctx, p.db, promoExistsSQL, []any{code}, &exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (p *promoCodeRepository) GetAll(ctx context.Context, limit, offset int) ([]*model.PromoCode, error) {
+	rows, err := p.QueryWithRetry(ctx, p.db, promoGetAllSQL, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var codes []*model.PromoCode
+	for rows.Next() {
+		promo, err := scanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, promo)
+	}
+	return codes, rows.Err()
+}
+
+func (p *promoCodeRepository) GetForUpdate(ctx context.Context, code string) (*model.PromoCode, error) {
+	rows, err := p.QueryWithRetry(ctx, p.db, promoGetForUpdateSQL, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanPromoCode(rows)
+}
+
+func (p *promoCodeRepository) Insert(ctx context.Context, promo *model.PromoCode) (int64, error) {
+	if err := p.QueryRowWithRetry(ctx, p.db, insertPromoSQL, []any{
+		time.Now(),
+		promo.Code,
+		promo.Amount,
+		promo.MaxRedemptions,
+		promo.PerUserLimit,
+		promo.StartsAt,
+		promo.EndsAt,
+		promo.SingleUse,
+	}, &promo.ID, &promo.CreatedAt); err != nil {
+		return -1, err
+	}
+	return promo.ID, nil
+}
+
+func (p *promoCodeRepository) UserRedemptions(ctx context.Context, promoID, userID int64) (int, error) {
+	var count int
+	if err := p.QueryRowWithRetry(ctx, p.db, promoUserRedemptionsSQL, []any{promoID, userID}, &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (p *promoCodeRepository) Redeem(ctx context.Context, promo *model.PromoCode, userID, transactionID int64) error {
+	if _, err := p.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return p.db.Exec(ctx, insertPromoRedemptionSQL, time.Now(), promo.ID, userID, transactionID)
+	}); err != nil {
+		return err
+	}
+	if _, err := p.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return p.db.Exec(ctx, updatePromoRedemptionsSQL, promo.ID)
+	}); err != nil {
+		return err
+	}
+	promo.Redemptions++
+	return nil
+}
+
+func scanPromoCode(rows pgx.Rows) (*model.PromoCode, error) {
+	var promo model.PromoCode
+	if err := rows.Scan(&promo.ID,
+		&promo.CreatedAt,
+		&promo.Code,
+		&promo.Amount,
+		&promo.MaxRedemptions,
+		&promo.PerUserLimit,
+		&promo.StartsAt,
+		&promo.EndsAt,
+		&promo.SingleUse,
+		&promo.Redemptions); err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func NewPromoCodeRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.PromoCodeRepository {
+	return &promoCodeRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
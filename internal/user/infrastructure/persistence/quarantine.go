@@ -0,0 +1,115 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	quarantineColumns = `id, created_at, order_id, provider, reason, reported_number, reported_status, reported_accrual,
+							reviewed_at, review_note`
+	insertQuarantineSQL = `INSERT INTO accrual_quarantine (order_id, provider, reason, reported_number, reported_status,
+							reported_accrual) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+	getQuarantineSQL        = `SELECT ` + quarantineColumns + ` FROM accrual_quarantine WHERE id = $1`
+	getPendingQuarantineSQL = `SELECT ` + quarantineColumns + ` FROM accrual_quarantine WHERE reviewed_at IS NULL
+							ORDER BY created_at DESC, id LIMIT $1 OFFSET $2`
+	// reviewQuarantineSQL only touches results not reviewed yet, so a result is reviewed once
+	reviewQuarantineSQL = `UPDATE accrual_quarantine SET reviewed_at = $1, review_note = $2
+							WHERE id = $3 AND reviewed_at IS NULL`
+)
+
+type quarantineRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (q *quarantineRepository) Insert(ctx context.Context, result *model.QuarantinedResult) (int64, error) {
+	if err := q.QueryRowWithRetry(ctx, q.db, insertQuarantineSQL, []any{
+		result.OrderID.Value,
+		result.Provider,
+		result.Reason,
+		result.Number,
+		result.Status,
+		result.Accrual,
+	}, &result.ID, &result.CreatedAt); err != nil {
+		return -1, err
+	}
+	return result.ID, nil
+}
+
+func (q *quarantineRepository) Get(ctx context.Context, id int64) (*model.QuarantinedResult, error) {
+	rows, err := q.QueryWithRetry(ctx, q.db, getQuarantineSQL, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanQuarantine(rows)
+}
+
+func (q *quarantineRepository) GetPending(ctx context.Context, limit, offset int) ([]*model.QuarantinedResult, error) {
+	rows, err := q.QueryWithRetry(ctx, q.db, getPendingQuarantineSQL, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var results []*model.QuarantinedResult
+	for rows.Next() {
+		result, err := scanQuarantine(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (q *quarantineRepository) Review(ctx context.Context, result *model.QuarantinedResult) error {
+	tag, err := q.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return q.db.Exec(ctx, reviewQuarantineSQL, result.ReviewedAt, nullString(result.Note), result.ID)
+	})
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrQuarantineReviewed
+	}
+	return nil
+}
+
+func scanQuarantine(rows pgx.Rows) (*model.QuarantinedResult, error) {
+	var result model.QuarantinedResult
+	var note *string
+	if err := rows.Scan(&result.ID,
+		&result.CreatedAt,
+		&result.OrderID.Value,
+		&result.Provider,
+		&result.Reason,
+		&result.Number,
+		&result.Status,
+		&result.Accrual,
+		&result.ReviewedAt,
+		&note); err != nil {
+		return nil, err
+	}
+	if note != nil {
+		result.Note = *note
+	}
+	return &result, nil
+}
+
+func NewQuarantineRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.QuarantineRepository {
+	return &quarantineRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
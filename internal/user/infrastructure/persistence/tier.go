@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	tierGetAllSQL    = `SELECT id, name, threshold, multiplier, perks FROM tiers ORDER BY threshold`
+	tierGetByUserSQL = `SELECT t.id, t.name, t.threshold, t.multiplier, t.perks
+							FROM tiers t JOIN users u ON u.tier_id = t.id WHERE u.id = $1`
+	tierHistorySQL = `SELECT user_id, changed_at, previous_tier_id, tier_id, rolling_accrued
+							FROM tier_history WHERE user_id = $1 ORDER BY changed_at, id`
+	// bonuses do not count towards the tier, neither do the reversals clawing them back
+	tierRollingAccruedSQL = `SELECT COALESCE(SUM(CASE WHEN t.type = 0 THEN t.amount ELSE -t.amount END), 0)
+							FROM transactions t LEFT JOIN transactions r ON r.id = t.reference_id
+							WHERE t.user_id = $1 AND t.created_at >= $2
+								AND (t.type = 0 OR (t.type = 3 AND COALESCE(r.type, 0) = 0))`
+	updateUserTierSQL   = `UPDATE users SET tier_id = $1 WHERE id = $2`
+	insertTierChangeSQL = `INSERT INTO tier_history (changed_at, user_id, previous_tier_id, tier_id, rolling_accrued)
+							VALUES ($1, $2, $3, $4, $5)`
+)
+
+type tierRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (t *tierRepository) GetAll(ctx context.Context) ([]*model.Tier, error) {
+	rows, err := t.QueryWithRetry(ctx, t.db, tierGetAllSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tiers []*model.Tier
+	for rows.Next() {
+		tier, err := scanTier(rows)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, rows.Err()
+}
+
+func (t *tierRepository) GetByUser(ctx context.Context, userID int64) (*model.Tier, error) {
+	rows, err := t.QueryWithRetry(ctx, t.db, tierGetByUserSQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanTier(rows)
+}
+
+func (t *tierRepository) GetHistory(ctx context.Context, userID int64) ([]*model.TierHistory, error) {
+	rows, err := t.QueryWithRetry(ctx, t.db, tierHistorySQL, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*model.TierHistory
+	for rows.Next() {
+		var item model.TierHistory
+		if err = rows.Scan(&item.UserID, &item.ChangedAt, &item.PreviousTierID, &item.TierID, &item.RollingAccrued); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+func (t *tierRepository) RollingAccrued(ctx context.Context, userID int64, since time.Time) (types.Decimal, error) {
+	var accrued types.Decimal
+	if err := t.QueryRowWithRetry(ctx, t.db, tierRollingAccruedSQL, []any{userID, since}, &accrued); err != nil {
+		return types.Decimal{}, err
+	}
+	return accrued, nil
+}
+
+func (t *tierRepository) Change(ctx context.Context, history *model.TierHistory) error {
+	if _, err := t.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return t.db.Exec(ctx, updateUserTierSQL, history.TierID, history.UserID)
+	}); err != nil {
+		return err
+	}
+	_, err := t.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return t.db.Exec(ctx, insertTierChangeSQL,
+			history.ChangedAt,
+			history.UserID,
+			history.PreviousTierID,
+			history.TierID,
+			history.RollingAccrued)
+	})
+	return err
+}
+
+func scanTier(rows pgx.Rows) (*model.Tier, error) {
+	var tier model.Tier
+	if err := rows.Scan(&tier.ID, &tier.Name, &tier.Threshold, &tier.Multiplier, &tier.Perks); err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+func NewTierRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.TierRepository {
+	return &tierRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReversedBonusShouldNotLowerRollingAccrued(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	unitOfWork := NewUnitOfWork(pool, db.NewRetryStrategy([]int{1, 3, 5}))
+	userID := testUser(t, unitOfWork)
+	tiers := application.NewTierEvaluator()
+	credit := func(orderID model.OrderID, accrual, bonus int64) {
+		_, err := unitOfWork.OrderRepository().Insert(ctx, &model.Order{OrderID: orderID, UserID: userID,
+			Status: model.OrderStatusPROCESSED, Provider: model.DefaultProvider})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		for tt, amount := range map[model.TransactionType]int64{model.ACCRUAL: accrual, model.BONUS: bonus} {
+			_, err = unitOfWork.BonusMovementRepository().Insert(ctx, &model.Transaction{UserID: userID, Type: tt,
+				OrderID: orderID, Amount: types.Decimal{Decimal: decimal.NewFromInt(amount)}})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+		}
+	}
+	evaluate := func() *model.Tier {
+		assert.NoError(t, unitOfWork.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+			return tiers.Evaluate(ctx, uow, userID)
+		}))
+		tier, err := unitOfWork.TierRepository().GetByUser(ctx, userID)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return tier
+	}
+	base := time.Now().UnixNano()
+	credit(model.OrderID{Value: base}, 1000, 50)
+	reversed := model.OrderID{Value: base + 1}
+	credit(reversed, 200, 10)
+	assert.Equal(t, "Silver", evaluate().Name)
+
+	_, err := application.NewTransactionService(unitOfWork).ReverseOrder(ctx, reversed, model.ACCRUAL,
+		model.ReasonAccrualCorrected)
+
+	assert.NoError(t, err)
+	accrued, err := unitOfWork.TierRepository().RollingAccrued(ctx, userID, time.Now().AddDate(0, -model.TierWindowMonths, 0))
+	assert.NoError(t, err)
+	assert.True(t, accrued.Equal(decimal.NewFromInt(1000)), accrued.String())
+	assert.Equal(t, "Silver", evaluate().Name, "the clawed back bonus should not demote the user")
+}
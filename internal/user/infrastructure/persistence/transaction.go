@@ -2,16 +2,44 @@ package persistence
 
 import (
 	"context"
+	"errors"
 	"github.com/DimKa163/gophermart/internal/shared/db"
 	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/repository"
 	"github.com/jackc/pgx/v5"
+	"time"
 )
 
 const (
-	transactionAllGetByTypeSQL = `SELECT created_at, user_id, type, amount, order_id FROM transactions WHERE user_id = $1 AND type = $2`
-	transactionAllGetSQL       = `SELECT created_at, user_id, type, amount, order_id FROM transactions WHERE user_id = $1`
+	transactionColumns = `t.id, t.created_at, t.user_id, t.type, t.amount, t.order_id, t.reference_id, t.reason,
+							t.transfer_id, COALESCE(tf.note, pc.code)`
+	transactionFrom = ` FROM transactions t
+									LEFT JOIN transfers tf ON tf.id = t.transfer_id
+									LEFT JOIN promo_redemptions pr ON pr.transaction_id = t.id
+									LEFT JOIN promo_codes pc ON pc.id = pr.promo_code_id`
+	transactionAllGetByTypeSQL = `SELECT ` + transactionColumns + transactionFrom + `
+									WHERE t.user_id = $1 AND t.type = $2`
+	transactionAllGetSQL = `SELECT ` + transactionColumns + transactionFrom + `
+									WHERE t.user_id = $1 ORDER BY t.created_at, t.id`
+	transactionGetForUpdateSQL = `SELECT ` + transactionColumns + transactionFrom + `
+									WHERE t.id = $1 FOR UPDATE OF t`
+	transactionGetByOrderForUpdateSQL = `SELECT ` + transactionColumns + transactionFrom + `
+									WHERE t.order_id = $1 AND t.type = $2
+									ORDER BY t.created_at DESC, t.id DESC LIMIT 1 FOR UPDATE OF t`
+	transactionPageSQL = `SELECT ` + transactionColumns + transactionFrom + `
+									WHERE t.user_id = $1 AND (t.created_at, t.id) > ($2, $3) AND t.created_at < $4
+										AND ($5::smallint[] IS NULL OR t.type = ANY($5))
+									ORDER BY t.created_at, t.id LIMIT $6`
+	transactionBalanceBeforeSQL = `SELECT COALESCE(SUM(CASE WHEN type IN (0, 2, 5, 7, 8) THEN amount ELSE -amount END), 0)
+									FROM transactions WHERE user_id = $1 AND (created_at, id) < ($2, $3)`
+	transactionTotalsSQL = `SELECT type, SUM(amount), COUNT(*) FROM transactions
+									WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+									GROUP BY type ORDER BY type`
+	transactionReversedSQL = `SELECT COUNT(*) FROM transactions WHERE reference_id = $1 AND type IN (2, 3)`
+	transactionInsertSQL   = `INSERT INTO transactions (created_at, user_id, type, amount, order_id, reference_id, reason, transfer_id)
+								VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+								ON CONFLICT (order_id, type) WHERE type IN (0, 5) DO NOTHING RETURNING id`
 )
 
 type bonusMovementRepository struct {
@@ -34,22 +62,172 @@ func (b bonusMovementRepository) GetAll(ctx context.Context, userID int64, tt *m
 	defer rows.Close()
 	var transactions []*model.Transaction
 	for rows.Next() {
-		var transaction model.Transaction
-		var orderID int64
-		var amountStr string
-		if err = rows.Scan(&transaction.CreatedAt, &transaction.UserID, &transaction.Type, &amountStr, &orderID); err != nil {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		transaction.OrderID = model.OrderID{
-			Value: orderID,
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func (b bonusMovementRepository) GetForUpdate(ctx context.Context, id int64) (*model.Transaction, error) {
+	rows, err := b.QueryWithRetry(ctx, b.db, transactionGetForUpdateSQL, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanTransaction(rows)
+}
+
+func (b bonusMovementRepository) GetByOrderForUpdate(ctx context.Context, orderID model.OrderID, tt model.TransactionType) (*model.Transaction, error) {
+	rows, err := b.QueryWithRetry(ctx, b.db, transactionGetByOrderForUpdateSQL, orderID.Value, tt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, err
 		}
-		transaction.Amount, err = types.NewDecimalFromString(amountStr)
+		return nil, pgx.ErrNoRows
+	}
+	return scanTransaction(rows)
+}
+
+func (b bonusMovementRepository) IsReversed(ctx context.Context, id int64) (bool, error) {
+	var count int
+	if err := b.QueryRowWithRetry(ctx, b.db, transactionReversedSQL, []any{id}, &count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (b bonusMovementRepository) BalanceBefore(ctx context.Context, userID int64, cursor model.StatementCursor) (types.Decimal, error) {
+	var balance types.Decimal
+	if err := b.QueryRowWithRetry(ctx, b.db, transactionBalanceBeforeSQL, []any{userID, cursor.CreatedAt, cursor.ID}, &balance); err != nil {
+		return types.Decimal{}, err
+	}
+	return balance, nil
+}
+
+func (b bonusMovementRepository) GetPage(ctx context.Context, userID int64, after model.StatementCursor, to time.Time, tt []model.TransactionType, limit int) ([]*model.Transaction, error) {
+	rows, err := b.QueryWithRetry(ctx, b.db, transactionPageSQL, userID, after.CreatedAt, after.ID, to, transactionTypes(tt), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var transactions []*model.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
-		transactions = append(transactions, &transaction)
+		transactions = append(transactions, transaction)
 	}
-	return transactions, nil
+	return transactions, rows.Err()
+}
+
+func (b bonusMovementRepository) Totals(ctx context.Context, userID int64, from, to time.Time) ([]*model.StatementTotal, error) {
+	rows, err := b.QueryWithRetry(ctx, b.db, transactionTotalsSQL, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var totals []*model.StatementTotal
+	for rows.Next() {
+		var total model.StatementTotal
+		if err = rows.Scan(&total.Type, &total.Amount, &total.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, &total)
+	}
+	return totals, rows.Err()
+}
+
+func (b bonusMovementRepository) Insert(ctx context.Context, transaction *model.Transaction) (int64, error) {
+	return insertTransaction(ctx, b.db, b.RetryStrategy, transaction)
+}
+
+// insertTransaction appends the movement and keeps the point lots in sync with it. Accruals and bonuses are
+// recorded once per order, a repeated one changes nothing and fails with model.ErrAlreadyCredited, so the
+// transaction saving it rolls back as a whole.
+func insertTransaction(ctx context.Context, qe db.QueryExecutor, retryStrategy *db.RetryStrategy, transaction *model.Transaction) (int64, error) {
+	var id int64
+	var reason *string
+	if transaction.Reason != "" {
+		r := string(transaction.Reason)
+		reason = &r
+	}
+	var orderID *int64
+	if transaction.OrderID != model.DefaultOrderID {
+		orderID = &transaction.OrderID.Value
+	}
+	if err := retryStrategy.QueryRowWithRetry(ctx, qe, transactionInsertSQL, []any{
+		time.Now(),
+		transaction.UserID,
+		transaction.Type,
+		transaction.Amount,
+		orderID,
+		transaction.ReferenceID,
+		reason,
+		transaction.TransferID,
+	}, &id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) && transaction.Type.OncePerOrder() {
+			return -1, model.ErrAlreadyCredited
+		}
+		return -1, err
+	}
+	transaction.ID = id
+	lots := &lotRepository{db: qe, RetryStrategy: retryStrategy}
+	var err error
+	switch {
+	case transaction.Type.IsCredit():
+		err = lots.add(ctx, transaction)
+	case transaction.Type.ConsumesLots():
+		err = lots.consume(ctx, transaction)
+	}
+	if err != nil {
+		return -1, err
+	}
+	return id, nil
+}
+
+func scanTransaction(rows pgx.Rows) (*model.Transaction, error) {
+	var transaction model.Transaction
+	var orderID *int64
+	var reason *string
+	var note *string
+	if err := rows.Scan(&transaction.ID,
+		&transaction.CreatedAt,
+		&transaction.UserID,
+		&transaction.Type,
+		&transaction.Amount,
+		&orderID,
+		&transaction.ReferenceID,
+		&reason,
+		&transaction.TransferID,
+		&note); err != nil {
+		return nil, err
+	}
+	if orderID != nil {
+		transaction.OrderID = model.OrderID{
+			Value: *orderID,
+		}
+	}
+	if reason != nil {
+		transaction.Reason = model.ReasonCode(*reason)
+	}
+	if note != nil {
+		transaction.Note = *note
+	}
+	return &transaction, nil
 }
 
 func NewBonusMovementRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.TransactionRepository {
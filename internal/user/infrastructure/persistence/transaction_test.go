@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/uow"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testPool connects to the database in TEST_DATABASE_URI and migrates it, the test is skipped without one.
+// CI provides the database with the postgres service of the gophermart workflow.
+func testPool(t *testing.T) *pgxpool.Pool {
+	uri := os.Getenv("TEST_DATABASE_URI")
+	if uri == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+	pool, err := pgxpool.New(context.Background(), uri)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(pool.Close)
+	// tests run in the package directory, the migrations are at the repository root
+	migrations, err := filepath.Abs("../../../../migrations")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	if !assert.NoError(t, Migrate(pool, "file://"+filepath.ToSlash(migrations))) {
+		t.FailNow()
+	}
+	return pool
+}
+
+type noRowsExecutor struct {
+	db.QueryExecutor
+}
+
+func (noRowsExecutor) QueryRow(context.Context, string, ...any) pgx.Row {
+	return noRows{}
+}
+
+type noRows struct{}
+
+func (noRows) Scan(...any) error {
+	return pgx.ErrNoRows
+}
+
+func TestInsertTransactionOnConflict(t *testing.T) {
+	cases := []struct {
+		name        string
+		tt          model.TransactionType
+		expectedErr error
+	}{
+		{
+			name:        "accrual is already credited",
+			tt:          model.ACCRUAL,
+			expectedErr: model.ErrAlreadyCredited,
+		},
+		{
+			name:        "bonus is already credited",
+			tt:          model.BONUS,
+			expectedErr: model.ErrAlreadyCredited,
+		},
+		{
+			name:        "withdrawal keeps the error",
+			tt:          model.WITHDRAWAL,
+			expectedErr: pgx.ErrNoRows,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr := &model.Transaction{UserID: 1, Type: c.tt, OrderID: model.OrderID{Value: 12345678903},
+				Amount: types.Decimal{Decimal: decimal.NewFromInt(500)}}
+			id, err := insertTransaction(context.Background(), noRowsExecutor{}, db.NewRetryStrategy([]int{1}), tr)
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Equal(t, int64(-1), id)
+		})
+	}
+}
+
+func TestOrderShouldBeCreditedOnceWhenTheResultIsReplayed(t *testing.T) {
+	ctx := context.Background()
+	pool := testPool(t)
+	unitOfWork := NewUnitOfWork(pool, db.NewRetryStrategy([]int{1, 3, 5}))
+	now := time.Now()
+	userID, err := unitOfWork.UserRepository().Insert(ctx, &model.User{
+		CreatedAt: now,
+		Login:     fmt.Sprintf("replay-%d", now.UnixNano()%1_000_000_000),
+		Password:  []byte("password"),
+		Salt:      []byte("salt"),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	orderID := model.OrderID{Value: now.UnixNano()}
+	_, err = unitOfWork.OrderRepository().Insert(ctx, &model.Order{OrderID: orderID, UserID: userID,
+		Status: model.OrderStatusPROCESSING, Provider: model.DefaultProvider})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	const replays = 5
+	errs := make([]error, replays)
+	var wg sync.WaitGroup
+	for i := 0; i < replays; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// every replay starts from the order as it was before the first result was saved
+			order := &model.Order{OrderID: orderID, UserID: userID, Status: model.OrderStatusPROCESSING,
+				Provider: model.DefaultProvider}
+			order.Status = model.OrderStatusPROCESSED
+			order.AddTransaction(model.ACCRUAL, types.Decimal{Decimal: decimal.NewFromInt(500)})
+			order.AddTransaction(model.BONUS, types.Decimal{Decimal: decimal.NewFromInt(25)})
+			errs[i] = unitOfWork.BeginTx(ctx, func(ctx context.Context, uow uow.UnitOfWork) error {
+				return uow.OrderRepository().UpdateChecked(ctx, order)
+			})
+		}()
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		assert.True(t, errors.Is(err, model.ErrAlreadyCredited), err.Error())
+	}
+	assert.Equal(t, 1, saved)
+	var accruals, bonuses int
+	var accrued types.Decimal
+	assert.NoError(t, pool.QueryRow(ctx, `SELECT COUNT(*) FILTER (WHERE type = 0), COUNT(*) FILTER (WHERE type = 5),
+		COALESCE(SUM(amount), 0) FROM transactions WHERE order_id = $1`, orderID.Value).Scan(&accruals, &bonuses, &accrued))
+	assert.Equal(t, 1, accruals)
+	assert.Equal(t, 1, bonuses)
+	assert.True(t, accrued.Equal(decimal.NewFromInt(525)), accrued.String())
+}
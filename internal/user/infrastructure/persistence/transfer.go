@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"time"
+)
+
+const (
+	insertTransferSQL = `INSERT INTO transfers (created_at, sender_id, recipient_id, amount, note)
+							VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	transferSentSQL = `SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM transfers WHERE sender_id = $1 AND created_at >= $2`
+)
+
+type transferRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (t *transferRepository) Insert(ctx context.Context, transfer *model.Transfer) (int64, error) {
+	var note *string
+	if transfer.Note != "" {
+		note = &transfer.Note
+	}
+	if err := t.QueryRowWithRetry(ctx, t.db, insertTransferSQL, []any{
+		time.Now(),
+		transfer.SenderID,
+		transfer.RecipientID,
+		transfer.Amount,
+		note,
+	}, &transfer.ID, &transfer.CreatedAt); err != nil {
+		return -1, err
+	}
+	return transfer.ID, nil
+}
+
+func (t *transferRepository) Sent(ctx context.Context, senderID int64, since time.Time) (types.Decimal, int, error) {
+	var amount types.Decimal
+	var count int
+	if err := t.QueryRowWithRetry(ctx, t.db, transferSentSQL, []any{senderID, since}, &amount, &count); err != nil {
+		return types.Decimal{}, 0, err
+	}
+	return amount, count, nil
+}
+
+func NewTransferRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.TransferRepository {
+	return &transferRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
@@ -34,6 +34,27 @@ func (u *unitOfWork) BonusBalanceRepository() repository.BonusBalanceRepository
 func (u *unitOfWork) BonusMovementRepository() repository.TransactionRepository {
 	return NewBonusMovementRepository(u.db, u.retryStrategy)
 }
+func (u *unitOfWork) ExportRepository() repository.ExportRepository {
+	return NewExportRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) LotRepository() repository.LotRepository {
+	return NewLotRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) PromoCodeRepository() repository.PromoCodeRepository {
+	return NewPromoCodeRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) QuarantineRepository() repository.QuarantineRepository {
+	return NewQuarantineRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) TierRepository() repository.TierRepository {
+	return NewTierRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) TransferRepository() repository.TransferRepository {
+	return NewTransferRepository(u.db, u.retryStrategy)
+}
+func (u *unitOfWork) WithdrawalRuleRepository() repository.WithdrawalRuleRepository {
+	return NewWithdrawalRuleRepository(u.db, u.retryStrategy)
+}
 func (u *unitOfWork) UserRepository() repository.UserRepository {
 	return NewUserRepository(u.db, u.retryStrategy)
 }
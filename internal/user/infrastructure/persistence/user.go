@@ -3,7 +3,6 @@ package persistence
 import (
 	"context"
 	"github.com/DimKa163/gophermart/internal/shared/db"
-	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
 	"github.com/DimKa163/gophermart/internal/user/domain/repository"
 )
@@ -13,6 +12,8 @@ const (
 	userGetSQL     = "SELECT id, created_at, login, password, salt FROM users WHERE login = $1"
 	insertUserSQL  = `INSERT INTO users (created_at, login, password, salt) VALUES ($1, $2, $3, $4) RETURNING id`
 	userCountSQL   = `SELECT COUNT(id) FROM users WHERE login = $1`
+	userExistsSQL  = `SELECT COUNT(id) FROM users WHERE id = $1`
+	userLockSQL    = `SELECT id FROM users WHERE id = $1 FOR UPDATE`
 )
 
 type userRepository struct {
@@ -22,27 +23,11 @@ type userRepository struct {
 
 func (u *userRepository) GetBonusBalanceByUserID(ctx context.Context, userID int64) (*model.BonusBalance, error) {
 	var balance model.BonusBalance
-	var err error
-	var currentStr string
-	var accrued string
-	var withdrawnStr string
-	if err = u.QueryRowWithRetry(ctx, u.db, userBalanceSQL, []any{userID},
+	if err := u.QueryRowWithRetry(ctx, u.db, userBalanceSQL, []any{userID},
 		&balance.UserID,
-		&currentStr,
-		&accrued,
-		&withdrawnStr); err != nil {
-		return nil, err
-	}
-	balance.Current, err = types.NewDecimalFromString(currentStr)
-	if err != nil {
-		return nil, err
-	}
-	balance.Accrued, err = types.NewDecimalFromString(accrued)
-	if err != nil {
-		return nil, err
-	}
-	balance.Withdrawn, err = types.NewDecimalFromString(withdrawnStr)
-	if err != nil {
+		&balance.Current,
+		&balance.Accrued,
+		&balance.Withdrawn); err != nil {
 		return nil, err
 	}
 	return &balance, nil
@@ -81,6 +66,20 @@ func (u *userRepository) LoginExists(ctx context.Context, login string) (bool, e
 	return count > 0, nil
 }
 
+func (u *userRepository) Exists(ctx context.Context, userID int64) (bool, error) {
+	var count int64
+	if err := u.QueryRowWithRetry(ctx, u.db, userExistsSQL, []any{userID}, &count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Lock serializes balance changes of the user until the end of the transaction.
+func (u *userRepository) Lock(ctx context.Context, userID int64) error {
+	var id int64
+	return u.QueryRowWithRetry(ctx, u.db, userLockSQL, []any{userID}, &id)
+}
+
 func NewUserRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.UserRepository {
 	return &userRepository{
 		db:            db,
@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/domain/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	withdrawalRuleColumns = `r.id, r.user_id, r.tier_id, r.min_amount, r.max_amount, r.daily_cap, r.monthly_cap,
+								r.max_order_share, r.cooling_off_seconds`
+	withdrawalRulesGetAllSQL = `SELECT ` + withdrawalRuleColumns + ` FROM withdrawal_rules r
+									ORDER BY r.tier_id NULLS LAST, r.user_id`
+	withdrawalRulesGetForUserSQL = `SELECT ` + withdrawalRuleColumns + ` FROM withdrawal_rules r
+									JOIN users u ON u.id = $1 AND (r.user_id = u.id OR r.tier_id = u.tier_id)
+									ORDER BY r.user_id NULLS FIRST`
+	upsertUserWithdrawalRuleSQL = `INSERT INTO withdrawal_rules (user_id, min_amount, max_amount, daily_cap, monthly_cap,
+									max_order_share, cooling_off_seconds) VALUES ($1, $2, $3, $4, $5, $6, $7)
+									ON CONFLICT (user_id) WHERE user_id IS NOT NULL DO UPDATE SET
+									min_amount = EXCLUDED.min_amount, max_amount = EXCLUDED.max_amount,
+									daily_cap = EXCLUDED.daily_cap, monthly_cap = EXCLUDED.monthly_cap,
+									max_order_share = EXCLUDED.max_order_share, cooling_off_seconds = EXCLUDED.cooling_off_seconds
+									RETURNING id`
+	upsertTierWithdrawalRuleSQL = `INSERT INTO withdrawal_rules (tier_id, min_amount, max_amount, daily_cap, monthly_cap,
+									max_order_share, cooling_off_seconds) VALUES ($1, $2, $3, $4, $5, $6, $7)
+									ON CONFLICT (tier_id) WHERE tier_id IS NOT NULL DO UPDATE SET
+									min_amount = EXCLUDED.min_amount, max_amount = EXCLUDED.max_amount,
+									daily_cap = EXCLUDED.daily_cap, monthly_cap = EXCLUDED.monthly_cap,
+									max_order_share = EXCLUDED.max_order_share, cooling_off_seconds = EXCLUDED.cooling_off_seconds
+									RETURNING id`
+	deleteWithdrawalRuleSQL = `DELETE FROM withdrawal_rules WHERE id = $1`
+	withdrawalUsageSQL      = `SELECT
+									COALESCE(SUM(CASE WHEN type = 1 AND created_at >= $2 THEN amount
+										WHEN type = 2 AND created_at >= $2 THEN -amount ELSE 0 END), 0),
+									COALESCE(SUM(CASE WHEN type = 1 AND created_at >= $3 THEN amount
+										WHEN type = 2 AND created_at >= $3 THEN -amount ELSE 0 END), 0),
+									MIN(CASE WHEN type = 0 THEN created_at END)
+								FROM transactions WHERE user_id = $1`
+)
+
+type withdrawalRuleRepository struct {
+	db db.QueryExecutor
+	*db.RetryStrategy
+}
+
+func (w *withdrawalRuleRepository) GetAll(ctx context.Context) ([]*model.WithdrawalRuleOverride, error) {
+	return w.query(ctx, withdrawalRulesGetAllSQL)
+}
+
+func (w *withdrawalRuleRepository) GetForUser(ctx context.Context, userID int64) ([]*model.WithdrawalRuleOverride, error) {
+	return w.query(ctx, withdrawalRulesGetForUserSQL, userID)
+}
+
+func (w *withdrawalRuleRepository) Save(ctx context.Context, override *model.WithdrawalRuleOverride) (int64, error) {
+	sql := upsertTierWithdrawalRuleSQL
+	var scope any = override.TierID
+	if override.UserID != nil {
+		sql = upsertUserWithdrawalRuleSQL
+		scope = override.UserID
+	}
+	var coolingOff *int64
+	if override.CoolingOff != nil {
+		seconds := int64(override.CoolingOff.Seconds())
+		coolingOff = &seconds
+	}
+	if err := w.QueryRowWithRetry(ctx, w.db, sql, []any{
+		scope,
+		override.MinAmount,
+		override.MaxAmount,
+		override.DailyCap,
+		override.MonthlyCap,
+		override.MaxOrderShare,
+		coolingOff,
+	}, &override.ID); err != nil {
+		return -1, err
+	}
+	return override.ID, nil
+}
+
+func (w *withdrawalRuleRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	tag, err := w.ExecWithRetry(ctx, func(ctx context.Context) (pgconn.CommandTag, error) {
+		return w.db.Exec(ctx, deleteWithdrawalRuleSQL, id)
+	})
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (w *withdrawalRuleRepository) Usage(ctx context.Context, userID int64, dayStart, monthStart time.Time) (*model.WithdrawalUsage, error) {
+	var usage model.WithdrawalUsage
+	if err := w.QueryRowWithRetry(ctx, w.db, withdrawalUsageSQL, []any{userID, dayStart, monthStart},
+		&usage.Day, &usage.Month, &usage.FirstAccrualAt); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (w *withdrawalRuleRepository) query(ctx context.Context, sql string, args ...any) ([]*model.WithdrawalRuleOverride, error) {
+	rows, err := w.QueryWithRetry(ctx, w.db, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var overrides []*model.WithdrawalRuleOverride
+	for rows.Next() {
+		override, err := scanWithdrawalRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, rows.Err()
+}
+
+func scanWithdrawalRule(rows pgx.Rows) (*model.WithdrawalRuleOverride, error) {
+	var override model.WithdrawalRuleOverride
+	var coolingOff *int64
+	if err := rows.Scan(&override.ID,
+		&override.UserID,
+		&override.TierID,
+		&override.MinAmount,
+		&override.MaxAmount,
+		&override.DailyCap,
+		&override.MonthlyCap,
+		&override.MaxOrderShare,
+		&coolingOff); err != nil {
+		return nil, err
+	}
+	if coolingOff != nil {
+		d := time.Duration(*coolingOff) * time.Second
+		override.CoolingOff = &d
+	}
+	return &override, nil
+}
+
+func NewWithdrawalRuleRepository(db db.QueryExecutor, retryStrategy *db.RetryStrategy) repository.WithdrawalRuleRepository {
+	return &withdrawalRuleRepository{
+		db:            db,
+		RetryStrategy: retryStrategy,
+	}
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"id", "processed_at", "type", "sum", "order", "reference_id", "reason", "transfer_id", "note"}
+
+type exportStorage struct {
+	dir string
+}
+
+// NewExportStorage keeps export files in a local directory, creating it when missing.
+func NewExportStorage(dir string) (domain.ExportStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &exportStorage{dir: dir}, nil
+}
+
+func (s *exportStorage) Create(export *model.Export) (string, domain.ExportWriter, error) {
+	file := fmt.Sprintf("export-%d-%d.%s", export.UserID, export.ID, export.Format)
+	f, err := os.OpenFile(s.Path(file), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", nil, err
+	}
+	if export.Format == model.ExportFormatCSV {
+		w, err := newCSVWriter(f)
+		if err != nil {
+			_ = f.Close()
+			return "", nil, err
+		}
+		return file, w, nil
+	}
+	return file, newNDJSONWriter(f), nil
+}
+
+func (s *exportStorage) Path(file string) string {
+	return filepath.Join(s.dir, filepath.Base(file))
+}
+
+func (s *exportStorage) Remove(file string) error {
+	if err := os.Remove(s.Path(file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+type csvWriter struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func newCSVWriter(f *os.File) (*csvWriter, error) {
+	w := csv.NewWriter(f)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return &csvWriter{f: f, w: w}, nil
+}
+
+func (c *csvWriter) Write(tr *model.Transaction) error {
+	record := exportRecord(tr)
+	return c.w.Write([]string{
+		strconv.FormatInt(record.ID, 10),
+		record.ProcessedAt.Format(time.RFC3339),
+		record.Type,
+		record.Sum,
+		record.Order,
+		optional(record.ReferenceID),
+		record.Reason,
+		optional(record.TransferID),
+		record.Note,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		_ = c.f.Close()
+		return err
+	}
+	return c.f.Close()
+}
+
+type ndjsonWriter struct {
+	f   *os.File
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(f *os.File) *ndjsonWriter {
+	buf := bufio.NewWriter(f)
+	return &ndjsonWriter{f: f, buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (n *ndjsonWriter) Write(tr *model.Transaction) error {
+	return n.enc.Encode(exportRecord(tr))
+}
+
+func (n *ndjsonWriter) Close() error {
+	if err := n.buf.Flush(); err != nil {
+		_ = n.f.Close()
+		return err
+	}
+	return n.f.Close()
+}
+
+type record struct {
+	ID          int64     `json:"id"`
+	ProcessedAt time.Time `json:"processed_at"`
+	Type        string    `json:"type"`
+	Sum         string    `json:"sum"`
+	Order       string    `json:"order,omitempty"`
+	ReferenceID *int64    `json:"reference_id,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	TransferID  *int64    `json:"transfer_id,omitempty"`
+	Note        string    `json:"note,omitempty"`
+}
+
+func exportRecord(tr *model.Transaction) record {
+	r := record{
+		ID:          tr.ID,
+		ProcessedAt: tr.CreatedAt,
+		Type:        tr.Type.String(),
+		Sum:         tr.Amount.String(),
+		ReferenceID: tr.ReferenceID,
+		Reason:      string(tr.Reason),
+		TransferID:  tr.TransferID,
+		Note:        tr.Note,
+	}
+	if tr.OrderID != model.DefaultOrderID {
+		r.Order = tr.OrderID.String()
+	}
+	return r
+}
+
+func optional(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/tripper"
+	"time"
+)
+
+type CircuitBreakerResponse struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	Requests            int        `json:"requests"`
+	Failures            int        `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+type TrackingRoleResponse struct {
+	Instance string    `json:"instance"`
+	Role     string    `json:"role"`
+	Since    time.Time `json:"since"`
+}
+
+type AccrualStatusResponse struct {
+	Tracking TrackingRoleResponse     `json:"tracking"`
+	Breakers []CircuitBreakerResponse `json:"circuit_breakers"`
+}
+
+// NewAccrualStatusResponse lists the circuit breakers in front of the accrual providers.
+func NewAccrualStatusResponse(leader db.LeaderStatus, statuses []tripper.BreakerStatus) AccrualStatusResponse {
+	breakers := make([]CircuitBreakerResponse, len(statuses))
+	for i, status := range statuses {
+		breakers[i] = CircuitBreakerResponse{
+			Name:                status.Name,
+			State:               status.State.String(),
+			Requests:            status.Requests,
+			Failures:            status.Failures,
+			ConsecutiveFailures: status.ConsecutiveFailures,
+		}
+		if !status.OpenedAt.IsZero() {
+			breakers[i].OpenedAt = &status.OpenedAt
+		}
+		if !status.RetryAt.IsZero() {
+			breakers[i].RetryAt = &status.RetryAt
+		}
+	}
+	return AccrualStatusResponse{
+		Tracking: TrackingRoleResponse{
+			Instance: leader.Instance,
+			Role:     leader.Role.String(),
+			Since:    leader.Since,
+		},
+		Breakers: breakers,
+	}
+}
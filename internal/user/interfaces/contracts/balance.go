@@ -1,8 +1,30 @@
 package contracts
 
-import "github.com/DimKa163/gophermart/internal/shared/types"
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
 
 type BalanceResponse struct {
 	Current   *types.Decimal `json:"current"`
 	Withdrawn *types.Decimal `json:"withdrawn"`
+	Accrued   *types.Decimal `json:"accrued,omitempty"`
+	At        *time.Time     `json:"at,omitempty"`
+	Expiring  []ExpiringItem `json:"expiring,omitempty"`
+}
+
+// NewBalanceAtResponse describes a balance computed as of a past moment.
+func NewBalanceAtResponse(balance *model.BonusBalance, at time.Time) BalanceResponse {
+	return BalanceResponse{
+		Current:   &balance.Current,
+		Withdrawn: &balance.Withdrawn,
+		Accrued:   &balance.Accrued,
+		At:        &at,
+	}
+}
+
+type ExpiringItem struct {
+	Sum       types.Decimal `json:"sum"`
+	ExpiresAt time.Time     `json:"expires_at"`
 }
@@ -0,0 +1,12 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+)
+
+// AccrualCallbackRequest is an accrual result pushed by the accrual service, it matches its order response.
+type AccrualCallbackRequest struct {
+	Order   string         `json:"order" binding:"required"`
+	Status  string         `json:"status" binding:"required"`
+	Accrual *types.Decimal `json:"accrual"`
+}
@@ -0,0 +1,50 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ResolveOrderRequest struct {
+	Status  string       `json:"status" binding:"required,oneof=PROCESSED INVALID"`
+	Accrual types.Points `json:"accrual"`
+}
+
+type DeadLetterResponse struct {
+	Number         string        `json:"number"`
+	UserID         int64         `json:"user_id"`
+	Status         string        `json:"status"`
+	Accrual        types.Decimal `json:"accrual,omitempty"`
+	UploadedAt     *time.Time    `json:"uploaded_at,omitempty"`
+	Provider       string        `json:"provider"`
+	Attempts       int           `json:"attempts"`
+	LastError      string        `json:"last_error,omitempty"`
+	ErrorClass     string        `json:"error_class,omitempty"`
+	NextCheckAt    time.Time     `json:"next_check_at"`
+	DeadLetteredAt *time.Time    `json:"dead_lettered_at,omitempty"`
+}
+
+func NewDeadLetterResponse(order *model.Order) DeadLetterResponse {
+	return DeadLetterResponse{
+		Number:         order.OrderID.String(),
+		UserID:         order.UserID,
+		Status:         order.Status.String(),
+		Accrual:        order.Accrual,
+		UploadedAt:     order.UploadedAt,
+		Provider:       order.Provider,
+		Attempts:       order.Attempts,
+		LastError:      order.Error,
+		ErrorClass:     string(order.ErrorClass),
+		NextCheckAt:    order.NextCheckAt,
+		DeadLetteredAt: order.DeadLetteredAt,
+	}
+}
+
+func NewDeadLetterResponses(orders []*model.Order) []DeadLetterResponse {
+	items := make([]DeadLetterResponse, len(orders))
+	for i, order := range orders {
+		items[i] = NewDeadLetterResponse(order)
+	}
+	return items
+}
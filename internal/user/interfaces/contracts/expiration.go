@@ -0,0 +1,39 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ExpirationReportResponse struct {
+	At     time.Time           `json:"at"`
+	DryRun bool                `json:"dry_run"`
+	Total  types.Decimal       `json:"total"`
+	Items  []ExpirationLotItem `json:"items"`
+}
+
+type ExpirationLotItem struct {
+	LotID     int64         `json:"lot_id"`
+	UserID    int64         `json:"user_id"`
+	Sum       types.Decimal `json:"sum"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+func NewExpirationReportResponse(report *model.ExpirationReport) ExpirationReportResponse {
+	items := make([]ExpirationLotItem, len(report.Items))
+	for i, item := range report.Items {
+		items[i] = ExpirationLotItem{
+			LotID:     item.LotID,
+			UserID:    item.UserID,
+			Sum:       item.Amount,
+			ExpiresAt: item.ExpiresAt,
+		}
+	}
+	return ExpirationReportResponse{
+		At:     report.At,
+		DryRun: report.DryRun,
+		Total:  report.Total,
+		Items:  items,
+	}
+}
@@ -0,0 +1,55 @@
+package contracts
+
+import (
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ExportRequest struct {
+	Format string     `json:"format" binding:"required"`
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+	Types  []string   `json:"types"`
+}
+
+type ExportResponse struct {
+	ID          int64      `json:"id"`
+	Status      string     `json:"status"`
+	Format      string     `json:"format"`
+	From        time.Time  `json:"from"`
+	To          time.Time  `json:"to"`
+	Types       []string   `json:"types,omitempty"`
+	Total       int        `json:"total"`
+	Done        int        `json:"done"`
+	Progress    int        `json:"progress"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func NewExportResponse(export *model.Export) ExportResponse {
+	response := ExportResponse{
+		ID:          export.ID,
+		Status:      export.Status.String(),
+		Format:      string(export.Format),
+		From:        export.From,
+		To:          export.To,
+		Total:       export.Total,
+		Done:        export.Done,
+		Progress:    export.Progress(),
+		Error:       export.Error,
+		ExpiresAt:   export.ExpiresAt,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+	}
+	for _, tt := range export.Types {
+		response.Types = append(response.Types, tt.String())
+	}
+	if export.Status == model.ExportStatusDONE {
+		response.DownloadURL = fmt.Sprintf("/api/user/exports/%d/download", export.ID)
+	}
+	return response
+}
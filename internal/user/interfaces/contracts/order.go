@@ -10,4 +10,5 @@ type OrderItem struct {
 	Status     string        `json:"status"`
 	Accrual    types.Decimal `json:"accrual,omitempty"`
 	UploadedAt *time.Time    `json:"uploaded_at,omitempty"`
+	Provider   string        `json:"provider,omitempty"`
 }
@@ -0,0 +1,64 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type RedeemCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type PromoCodeRequest struct {
+	Code           string       `json:"code" binding:"required,max=32"`
+	Sum            types.Points `json:"sum"`
+	MaxRedemptions int          `json:"max_redemptions" binding:"min=0"`
+	PerUserLimit   *int         `json:"per_user_limit" binding:"omitempty,min=0"`
+	StartsAt       *time.Time   `json:"starts_at"`
+	EndsAt         *time.Time   `json:"ends_at"`
+}
+
+type GiftCodesRequest struct {
+	Prefix   string       `json:"prefix" binding:"max=16"`
+	Sum      types.Points `json:"sum"`
+	Count    int          `json:"count" binding:"required,min=1,max=1000"`
+	StartsAt *time.Time   `json:"starts_at"`
+	EndsAt   *time.Time   `json:"ends_at"`
+}
+
+type PromoCodeResponse struct {
+	ID             int64         `json:"id"`
+	Code           string        `json:"code"`
+	Sum            types.Decimal `json:"sum"`
+	MaxRedemptions int           `json:"max_redemptions"`
+	PerUserLimit   int           `json:"per_user_limit"`
+	StartsAt       *time.Time    `json:"starts_at,omitempty"`
+	EndsAt         *time.Time    `json:"ends_at,omitempty"`
+	SingleUse      bool          `json:"single_use"`
+	Redemptions    int           `json:"redemptions"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+func NewPromoCodeResponse(promo *model.PromoCode) PromoCodeResponse {
+	return PromoCodeResponse{
+		ID:             promo.ID,
+		Code:           promo.Code,
+		Sum:            promo.Amount,
+		MaxRedemptions: promo.MaxRedemptions,
+		PerUserLimit:   promo.PerUserLimit,
+		StartsAt:       promo.StartsAt,
+		EndsAt:         promo.EndsAt,
+		SingleUse:      promo.SingleUse,
+		Redemptions:    promo.Redemptions,
+		CreatedAt:      promo.CreatedAt,
+	}
+}
+
+func NewPromoCodeResponses(codes []*model.PromoCode) []PromoCodeResponse {
+	responses := make([]PromoCodeResponse, len(codes))
+	for i, promo := range codes {
+		responses[i] = NewPromoCodeResponse(promo)
+	}
+	return responses
+}
@@ -0,0 +1,47 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ReviewQuarantineRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+type QuarantineResponse struct {
+	ID              int64          `json:"id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Order           string         `json:"order"`
+	Provider        string         `json:"provider"`
+	Reason          string         `json:"reason"`
+	ReportedNumber  string         `json:"reported_number"`
+	ReportedStatus  string         `json:"reported_status"`
+	ReportedAccrual *types.Decimal `json:"reported_accrual,omitempty"`
+	ReviewedAt      *time.Time     `json:"reviewed_at,omitempty"`
+	Note            string         `json:"note,omitempty"`
+}
+
+func NewQuarantineResponse(result *model.QuarantinedResult) QuarantineResponse {
+	return QuarantineResponse{
+		ID:              result.ID,
+		CreatedAt:       result.CreatedAt,
+		Order:           result.OrderID.String(),
+		Provider:        result.Provider,
+		Reason:          string(result.Reason),
+		ReportedNumber:  result.Number,
+		ReportedStatus:  result.Status,
+		ReportedAccrual: result.Accrual,
+		ReviewedAt:      result.ReviewedAt,
+		Note:            result.Note,
+	}
+}
+
+func NewQuarantineResponses(results []*model.QuarantinedResult) []QuarantineResponse {
+	items := make([]QuarantineResponse, len(results))
+	for i, result := range results {
+		items[i] = NewQuarantineResponse(result)
+	}
+	return items
+}
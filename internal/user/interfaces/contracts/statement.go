@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type StatementEntryResponse struct {
+	TransactionResponse
+	Balance types.Decimal `json:"balance"`
+}
+
+type StatementTotalResponse struct {
+	Type  string        `json:"type"`
+	Sum   types.Decimal `json:"sum"`
+	Count int           `json:"count"`
+}
+
+type StatementResponse struct {
+	From       time.Time                `json:"from"`
+	To         time.Time                `json:"to"`
+	Opening    types.Decimal            `json:"opening_balance"`
+	Closing    types.Decimal            `json:"closing_balance"`
+	Totals     []StatementTotalResponse `json:"totals"`
+	Entries    []StatementEntryResponse `json:"entries"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+}
+
+func NewStatementResponse(statement *model.Statement) StatementResponse {
+	response := StatementResponse{
+		From:    statement.From,
+		To:      statement.To,
+		Opening: statement.Opening,
+		Closing: statement.Closing,
+		Totals:  make([]StatementTotalResponse, len(statement.Totals)),
+		Entries: make([]StatementEntryResponse, len(statement.Entries)),
+	}
+	for i, total := range statement.Totals {
+		response.Totals[i] = StatementTotalResponse{
+			Type:  total.Type.String(),
+			Sum:   total.Amount,
+			Count: total.Count,
+		}
+	}
+	for i, entry := range statement.Entries {
+		response.Entries[i] = StatementEntryResponse{
+			TransactionResponse: NewTransactionResponse(entry.Transaction),
+			Balance:             entry.Balance,
+		}
+	}
+	if statement.Next != nil {
+		response.NextCursor = statement.Next.String()
+	}
+	return response
+}
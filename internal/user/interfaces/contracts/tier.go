@@ -0,0 +1,52 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type TierItem struct {
+	Name       string        `json:"name"`
+	Threshold  types.Decimal `json:"threshold"`
+	Multiplier types.Decimal `json:"multiplier"`
+	Perks      []string      `json:"perks"`
+}
+
+type TierResponse struct {
+	Current        *TierItem      `json:"current"`
+	Next           *TierItem      `json:"next,omitempty"`
+	RollingAccrued types.Decimal  `json:"rolling_accrued"`
+	Remaining      *types.Decimal `json:"remaining,omitempty"`
+}
+
+type TierHistoryItem struct {
+	PreviousTierID *int          `json:"previous_tier_id"`
+	TierID         *int          `json:"tier_id"`
+	RollingAccrued types.Decimal `json:"rolling_accrued"`
+	ChangedAt      time.Time     `json:"changed_at"`
+}
+
+func NewTierResponse(progress *model.TierProgress) TierResponse {
+	response := TierResponse{
+		Current:        newTierItem(progress.Current),
+		Next:           newTierItem(progress.Next),
+		RollingAccrued: progress.RollingAccrued,
+	}
+	if progress.Next != nil {
+		response.Remaining = &progress.Remaining
+	}
+	return response
+}
+
+func newTierItem(tier *model.Tier) *TierItem {
+	if tier == nil {
+		return nil
+	}
+	return &TierItem{
+		Name:       tier.Name,
+		Threshold:  tier.Threshold,
+		Multiplier: tier.Multiplier,
+		Perks:      tier.Perks,
+	}
+}
@@ -0,0 +1,59 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/user/interfaces/worker"
+	"time"
+)
+
+type TrackingSettingsRequest struct {
+	BatchSize *int    `json:"batch_size" binding:"omitempty,min=1"`
+	Schedule  *string `json:"schedule" binding:"omitempty,min=1"`
+}
+
+type TrackingCycleResponse struct {
+	Source          string         `json:"source"`
+	StartedAt       time.Time      `json:"started_at"`
+	DurationMs      int64          `json:"duration_ms"`
+	Checked         int            `json:"checked"`
+	Failed          int            `json:"failed"`
+	DeadLettered    int            `json:"dead_lettered"`
+	Quarantined     int            `json:"quarantined"`
+	Released        int            `json:"released"`
+	AlreadyCredited int            `json:"already_credited"`
+	Transitions     map[string]int `json:"transitions,omitempty"`
+	Error           string         `json:"error,omitempty"`
+}
+
+type TrackingStatusResponse struct {
+	Paused    bool                    `json:"paused"`
+	Running   bool                    `json:"running"`
+	Schedule  string                  `json:"schedule"`
+	BatchSize int                     `json:"batch_size"`
+	Cycles    []TrackingCycleResponse `json:"cycles"`
+}
+
+func NewTrackingStatusResponse(status worker.WorkerStatus) TrackingStatusResponse {
+	cycles := make([]TrackingCycleResponse, len(status.Cycles))
+	for i, cycle := range status.Cycles {
+		cycles[i] = TrackingCycleResponse{
+			Source:          cycle.Source,
+			StartedAt:       cycle.StartedAt,
+			DurationMs:      cycle.Duration.Milliseconds(),
+			Checked:         cycle.Checked,
+			Failed:          cycle.Failed,
+			DeadLettered:    cycle.DeadLettered,
+			Quarantined:     cycle.Quarantined,
+			Released:        cycle.Released,
+			AlreadyCredited: cycle.AlreadyCredited,
+			Transitions:     cycle.Transitions,
+			Error:           cycle.Error,
+		}
+	}
+	return TrackingStatusResponse{
+		Paused:    status.Paused,
+		Running:   status.Running,
+		Schedule:  status.Schedule,
+		BatchSize: status.BatchSize,
+		Cycles:    cycles,
+	}
+}
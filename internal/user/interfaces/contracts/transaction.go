@@ -0,0 +1,41 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+type ReversalRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type TransactionResponse struct {
+	ID          int64          `json:"id"`
+	Type        string         `json:"type"`
+	OrderID     *model.OrderID `json:"order,omitempty"`
+	Sum         types.Decimal  `json:"sum"`
+	ReferenceID *int64         `json:"reference_id,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	TransferID  *int64         `json:"transfer_id,omitempty"`
+	Note        string         `json:"note,omitempty"`
+	ProcessedAt time.Time      `json:"processed_at"`
+}
+
+func NewTransactionResponse(tr *model.Transaction) TransactionResponse {
+	response := TransactionResponse{
+		ID:          tr.ID,
+		Type:        tr.Type.String(),
+		Sum:         tr.Amount,
+		ReferenceID: tr.ReferenceID,
+		Reason:      string(tr.Reason),
+		TransferID:  tr.TransferID,
+		Note:        tr.Note,
+		ProcessedAt: tr.CreatedAt,
+	}
+	if tr.OrderID != model.DefaultOrderID {
+		orderID := tr.OrderID
+		response.OrderID = &orderID
+	}
+	return response
+}
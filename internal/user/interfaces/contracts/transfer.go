@@ -0,0 +1,20 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"time"
+)
+
+type TransferRequest struct {
+	Login string       `json:"login" binding:"required"`
+	Sum   types.Points `json:"sum"`
+	Note  string       `json:"note" binding:"max=140"`
+}
+
+type TransferResponse struct {
+	ID          int64         `json:"id"`
+	Login       string        `json:"login"`
+	Sum         types.Decimal `json:"sum"`
+	Note        string        `json:"note,omitempty"`
+	ProcessedAt time.Time     `json:"processed_at"`
+}
@@ -8,7 +8,8 @@ import (
 
 type WithdrawRequest struct {
 	OrderID model.OrderID `json:"order"`
-	Sum     types.Decimal `json:"sum"`
+	Sum     types.Points  `json:"sum"`
+	Total   *types.Points `json:"order_total,omitempty"`
 }
 
 type WithdrawResponse struct {
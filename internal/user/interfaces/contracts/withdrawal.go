@@ -0,0 +1,79 @@
+package contracts
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"time"
+)
+
+// WithdrawalRulesResponse lists the effective rules, zero values mean the rule is disabled.
+type WithdrawalRulesResponse struct {
+	MinAmount     types.Decimal `json:"min_amount"`
+	MaxAmount     types.Decimal `json:"max_amount"`
+	DailyCap      types.Decimal `json:"daily_cap"`
+	MonthlyCap    types.Decimal `json:"monthly_cap"`
+	MaxOrderShare types.Decimal `json:"max_order_share"`
+	CoolingOff    string        `json:"cooling_off"`
+}
+
+func NewWithdrawalRulesResponse(rules model.WithdrawalRules) WithdrawalRulesResponse {
+	return WithdrawalRulesResponse{
+		MinAmount:     rules.MinAmount,
+		MaxAmount:     rules.MaxAmount,
+		DailyCap:      rules.DailyCap,
+		MonthlyCap:    rules.MonthlyCap,
+		MaxOrderShare: rules.MaxOrderShare,
+		CoolingOff:    rules.CoolingOff.String(),
+	}
+}
+
+// WithdrawalRuleOverride is the admin representation of an override, null fields are inherited.
+type WithdrawalRuleOverride struct {
+	ID            int64          `json:"id"`
+	UserID        *int64         `json:"user_id"`
+	TierID        *int           `json:"tier_id"`
+	MinAmount     *types.Decimal `json:"min_amount"`
+	MaxAmount     *types.Decimal `json:"max_amount"`
+	DailyCap      *types.Decimal `json:"daily_cap"`
+	MonthlyCap    *types.Decimal `json:"monthly_cap"`
+	MaxOrderShare *types.Decimal `json:"max_order_share"`
+	CoolingOff    *string        `json:"cooling_off"`
+}
+
+func NewWithdrawalRuleOverride(override *model.WithdrawalRuleOverride) WithdrawalRuleOverride {
+	response := WithdrawalRuleOverride{
+		ID:            override.ID,
+		UserID:        override.UserID,
+		TierID:        override.TierID,
+		MinAmount:     override.MinAmount,
+		MaxAmount:     override.MaxAmount,
+		DailyCap:      override.DailyCap,
+		MonthlyCap:    override.MonthlyCap,
+		MaxOrderShare: override.MaxOrderShare,
+	}
+	if override.CoolingOff != nil {
+		coolingOff := override.CoolingOff.String()
+		response.CoolingOff = &coolingOff
+	}
+	return response
+}
+
+func (o *WithdrawalRuleOverride) Model() (*model.WithdrawalRuleOverride, error) {
+	override := &model.WithdrawalRuleOverride{
+		UserID:        o.UserID,
+		TierID:        o.TierID,
+		MinAmount:     o.MinAmount,
+		MaxAmount:     o.MaxAmount,
+		DailyCap:      o.DailyCap,
+		MonthlyCap:    o.MonthlyCap,
+		MaxOrderShare: o.MaxOrderShare,
+	}
+	if o.CoolingOff != nil {
+		coolingOff, err := time.ParseDuration(*o.CoolingOff)
+		if err != nil {
+			return nil, err
+		}
+		override.CoolingOff = &coolingOff
+	}
+	return override, nil
+}
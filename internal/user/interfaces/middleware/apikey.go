@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKey guards service-to-service groups (admin, partner). An empty key
+// disables the group entirely.
+func APIKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(APIKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(value), []byte(key)) != 1 {
+			logging.Logger(c).Warn("Authorization Error: invalid api key")
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
+// maxSignedBodySize bounds the body read into memory before the signature is checked, larger bodies are rejected.
+const maxSignedBodySize = 1 << 20
+
+// Signature guards inbound callbacks signed with a shared secret, see auth.Sign.
+// An empty secret disables the group entirely.
+func Signature(secret string, tolerance time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := logging.Logger(c)
+		if secret == "" {
+			logger.Warn("Authorization Error: callbacks are disabled")
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodySize))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			logger.Warn("signed body is too large", zap.Int64("limit", tooLarge.Limit))
+			c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			logger.Error("error reading body", zap.Error(err))
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		if err = auth.VerifySignature([]byte(secret), c.GetHeader(SignatureTimestampHeader), body,
+			c.GetHeader(SignatureHeader), time.Now(), tolerance); err != nil {
+			logger.Warn("Authorization Error", zap.Error(err))
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/DimKa163/gophermart/internal/shared/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testSecret = "secret"
+
+func signedRequest(body []byte) *http.Request {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Set(SignatureTimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, auth.Sign([]byte(testSecret), timestamp, body))
+	return req
+}
+
+func serveSigned(req *http.Request) (*httptest.ResponseRecorder, []byte) {
+	gin.SetMode(gin.TestMode)
+	var received []byte
+	router := gin.New()
+	router.POST("/callback", Signature(testSecret, time.Minute), func(c *gin.Context) {
+		received, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w, received
+}
+
+func TestSignatureWithValidSignatureShouldPassBody(t *testing.T) {
+	body := []byte(`{"order":"12345678903"}`)
+
+	w, received := serveSigned(signedRequest(body))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, received)
+}
+
+func TestSignatureWithOversizedBodyShouldReturnTooLarge(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), maxSignedBodySize+1)
+
+	w, received := serveSigned(signedRequest(body))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Nil(t, received, "the handler should not be called")
+}
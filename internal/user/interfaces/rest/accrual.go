@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/tripper"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type AccrualAPI interface {
+	Status(context *gin.Context)
+}
+
+type accrualAPI struct {
+	breakers []*tripper.CircuitBreaker
+	leader   *db.LeaderElector
+}
+
+func NewAccrualAPI(breakers []*tripper.CircuitBreaker, leader *db.LeaderElector) AccrualAPI {
+	return &accrualAPI{
+		breakers: breakers,
+		leader:   leader,
+	}
+}
+
+// Status reports the role of this instance in order tracking and the state of the circuit breakers
+// in front of the accrual providers.
+func (a *accrualAPI) Status(context *gin.Context) {
+	statuses := make([]tripper.BreakerStatus, len(a.breakers))
+	for i, breaker := range a.breakers {
+		statuses[i] = breaker.Status()
+	}
+	response := contracts.NewAccrualStatusResponse(a.leader.Status(), statuses)
+	context.JSON(http.StatusOK, &response)
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AdminAPI interface {
+	Reverse(context *gin.Context)
+	Expire(context *gin.Context)
+	Balance(context *gin.Context)
+}
+
+type adminAPI struct {
+	transaction domain.TransactionService
+	expiration  domain.ExpirationService
+	balance     domain.BalanceService
+}
+
+func NewAdminAPI(transaction domain.TransactionService,
+	expiration domain.ExpirationService,
+	balance domain.BalanceService) AdminAPI {
+	return &adminAPI{
+		transaction: transaction,
+		expiration:  expiration,
+		balance:     balance,
+	}
+}
+
+func (a *adminAPI) Reverse(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reason, ok := bindReason(context, logger)
+	if !ok {
+		return
+	}
+	result, err := a.transaction.Reverse(context, id, reason)
+	if err != nil {
+		writeReversalError(context, logger, err)
+		return
+	}
+	response := contracts.NewTransactionResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+// Expire runs the points expiration immediately, dry_run=true only reports what would expire.
+func (a *adminAPI) Expire(context *gin.Context) {
+	logger := logging.Logger(context)
+	dryRun, err := strconv.ParseBool(context.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	at := time.Now()
+	if value := context.Query("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !dryRun && at.After(time.Now()) {
+			context.JSON(http.StatusBadRequest, gin.H{"error": "points can not be expired ahead of time"})
+			return
+		}
+	}
+	report, err := a.expiration.Expire(context, at, dryRun)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewExpirationReportResponse(report)
+	context.JSON(http.StatusOK, &response)
+}
+
+// Balance returns the balance of any user, as of now unless at is given.
+func (a *adminAPI) Balance(context *gin.Context) {
+	logger := logging.Logger(context)
+	userID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	at := time.Now()
+	if value := context.Query("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	result, err := a.balance.At(context, userID, at)
+	if err != nil {
+		if errors.Is(err, application.ErrUserNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewBalanceAtResponse(result, at)
+	context.JSON(http.StatusOK, &response)
+}
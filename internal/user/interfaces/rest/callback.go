@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type CallbackAPI interface {
+	Accrual(context *gin.Context)
+}
+
+type callbackAPI struct {
+	callback domain.AccrualCallbackService
+}
+
+func NewCallbackAPI(callback domain.AccrualCallbackService) CallbackAPI {
+	return &callbackAPI{callback: callback}
+}
+
+// Accrual takes an accrual result pushed by the accrual service. Repeated callbacks are answered
+// the same way as the first one and do not credit the order twice.
+func (c *callbackAPI) Accrual(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.AccrualCallbackRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	orderID, err := model.NewOrderID(body.Order)
+	if err != nil {
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := c.callback.Apply(context, orderID, body.Status, body.Accrual)
+	if err != nil {
+		var notFound *domain.ResourceNotFound
+		switch {
+		case errors.As(err, &notFound):
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrAlreadyCredited):
+			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrAccrualQuarantined), types.IsInvalidPoints(err):
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	context.JSON(http.StatusOK, &contracts.OrderItem{
+		Number:     result.OrderID.String(),
+		Status:     result.Status.String(),
+		Accrual:    result.Accrual,
+		UploadedAt: result.UploadedAt,
+		Provider:   result.Provider,
+	})
+}
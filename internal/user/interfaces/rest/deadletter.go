@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+const defaultDeadLetterPageSize = 100
+
+type DeadLetterAPI interface {
+	List(context *gin.Context)
+	Get(context *gin.Context)
+	Requeue(context *gin.Context)
+	Resolve(context *gin.Context)
+}
+
+type deadLetterAPI struct {
+	deadLetter domain.DeadLetterService
+}
+
+func NewDeadLetterAPI(deadLetter domain.DeadLetterService) DeadLetterAPI {
+	return &deadLetterAPI{deadLetter: deadLetter}
+}
+
+func (d *deadLetterAPI) List(context *gin.Context) {
+	logger := logging.Logger(context)
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", strconv.Itoa(defaultDeadLetterPageSize)))
+	if err != nil || limit <= 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+	result, err := d.deadLetter.List(context, limit, offset)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	context.JSON(http.StatusOK, contracts.NewDeadLetterResponses(result))
+}
+
+func (d *deadLetterAPI) Get(context *gin.Context) {
+	logger := logging.Logger(context)
+	orderID, ok := bindOrderNumber(context)
+	if !ok {
+		return
+	}
+	result, err := d.deadLetter.Get(context, orderID)
+	if err != nil {
+		writeDeadLetterError(context, logger, err)
+		return
+	}
+	response := contracts.NewDeadLetterResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+// Requeue hands the order back to order tracking, it is checked with the accrual service on the next run.
+func (d *deadLetterAPI) Requeue(context *gin.Context) {
+	logger := logging.Logger(context)
+	orderID, ok := bindOrderNumber(context)
+	if !ok {
+		return
+	}
+	result, err := d.deadLetter.Requeue(context, orderID)
+	if err != nil {
+		writeDeadLetterError(context, logger, err)
+		return
+	}
+	response := contracts.NewDeadLetterResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+// Resolve settles the order without the accrual service, as PROCESSED with the given accrual or as INVALID.
+func (d *deadLetterAPI) Resolve(context *gin.Context) {
+	logger := logging.Logger(context)
+	orderID, ok := bindOrderNumber(context)
+	if !ok {
+		return
+	}
+	var body contracts.ResolveOrderRequest
+	if err := context.ShouldBind(&body); err != nil {
+		writeBindError(context, logger, err)
+		return
+	}
+	status := model.OrderStatusINVALID
+	if body.Status == model.OrderStatusPROCESSED.String() {
+		status = model.OrderStatusPROCESSED
+	}
+	result, err := d.deadLetter.Resolve(context, orderID, status, body.Accrual.Decimal())
+	if err != nil {
+		writeDeadLetterError(context, logger, err)
+		return
+	}
+	response := contracts.NewDeadLetterResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func bindOrderNumber(context *gin.Context) (model.OrderID, bool) {
+	orderID, err := model.NewOrderID(context.Param("number"))
+	if err != nil {
+		if errors.Is(err, model.ErrOrderID) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return model.DefaultOrderID, false
+		}
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.DefaultOrderID, false
+	}
+	return orderID, true
+}
+
+func writeDeadLetterError(context *gin.Context, logger *zap.Logger, err error) {
+	var notFound *domain.ResourceNotFound
+	switch {
+	case errors.As(err, &notFound):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, model.ErrNotDeadLettered), errors.Is(err, model.ErrAlreadyCredited):
+		context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, model.ErrResolveStatus):
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
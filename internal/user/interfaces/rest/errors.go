@@ -0,0 +1,55 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/types"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+func writeReversalError(context *gin.Context, logger *zap.Logger, err error) {
+	var notFound *domain.ResourceNotFound
+	switch {
+	case errors.As(err, &notFound):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, model.ErrTransactionReversed):
+		context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, model.ErrNotReversible):
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrNegativeBalance):
+		context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	default:
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// writeBindError answers 422 for amounts out of the points range and 400 for any other malformed body.
+func writeBindError(context *gin.Context, logger *zap.Logger, err error) {
+	logger.Error("error reading body", zap.Error(err))
+	if types.IsInvalidPoints(err) {
+		context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func bindReason(context *gin.Context, logger *zap.Logger) (model.ReasonCode, bool) {
+	var body contracts.ReversalRequest
+	if err := context.ShouldBind(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	reason, err := model.NewReasonCode(body.Reason)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return reason, true
+}
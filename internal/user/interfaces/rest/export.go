@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ExportAPI interface {
+	Create(context *gin.Context)
+	Get(context *gin.Context)
+	Download(context *gin.Context)
+}
+
+type exportAPI struct {
+	export domain.ExportService
+}
+
+func NewExportAPI(export domain.ExportService) ExportAPI {
+	return &exportAPI{
+		export: export,
+	}
+}
+
+func (e *exportAPI) Create(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.ExportRequest
+	if err := context.ShouldBind(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, err := model.NewExportFormat(body.Format)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var tt []model.TransactionType
+	for _, name := range body.Types {
+		t, err := model.ParseTransactionType(name)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		tt = append(tt, t)
+	}
+	var from time.Time
+	if body.From != nil {
+		from = *body.From
+	}
+	to := time.Now()
+	if body.To != nil {
+		to = *body.To
+	}
+	result, err := e.export.Create(context, format, from, to, tt)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidPeriod) {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewExportResponse(result)
+	context.JSON(http.StatusAccepted, &response)
+}
+
+func (e *exportAPI) Get(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := e.export.Get(context, id)
+	if err != nil {
+		if errors.Is(err, application.ErrExportNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewExportResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (e *exportAPI) Download(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, path, err := e.export.Download(context, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrExportNotFound):
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrExportNotReady):
+			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrExportExpired):
+			context.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	context.Header("Content-Type", result.Format.ContentType())
+	context.FileAttachment(path, result.File)
+}
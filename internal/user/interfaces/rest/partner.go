@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type PartnerAPI interface {
+	Refund(context *gin.Context)
+	Reverse(context *gin.Context)
+}
+
+type partnerAPI struct {
+	transaction domain.TransactionService
+}
+
+func NewPartnerAPI(transaction domain.TransactionService) PartnerAPI {
+	return &partnerAPI{
+		transaction: transaction,
+	}
+}
+
+// Refund gives back the points withdrawn for the order, e.g. after the goods were returned.
+func (p *partnerAPI) Refund(context *gin.Context) {
+	p.reverseOrder(context, model.WITHDRAWAL)
+}
+
+// Reverse claws back the points accrued for the order after the accrual was corrected.
+func (p *partnerAPI) Reverse(context *gin.Context) {
+	p.reverseOrder(context, model.ACCRUAL)
+}
+
+func (p *partnerAPI) reverseOrder(context *gin.Context, tt model.TransactionType) {
+	logger := logging.Logger(context)
+	orderID, err := model.NewOrderID(context.Param("number"))
+	if err != nil {
+		if errors.Is(err, model.ErrOrderID) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reason, ok := bindReason(context, logger)
+	if !ok {
+		return
+	}
+	result, err := p.transaction.ReverseOrder(context, orderID, tt, reason)
+	if err != nil {
+		writeReversalError(context, logger, err)
+		return
+	}
+	response := contracts.NewTransactionResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+const defaultPromoPageSize = 100
+
+type PromoAPI interface {
+	Redeem(context *gin.Context)
+	Create(context *gin.Context)
+	Generate(context *gin.Context)
+	List(context *gin.Context)
+}
+
+type promoAPI struct {
+	promo domain.PromoService
+}
+
+func NewPromoAPI(promo domain.PromoService) PromoAPI {
+	return &promoAPI{
+		promo: promo,
+	}
+}
+
+func (p *promoAPI) Redeem(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.RedeemCodeRequest
+	if err := context.ShouldBind(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := p.promo.Redeem(context, body.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrPromoCodeNotFound):
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrPromoCodeNotActive):
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrPromoCodeExhausted):
+			context.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrPromoCodeAlreadyRedeemed):
+			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	response := contracts.NewTransactionResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (p *promoAPI) Create(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.PromoCodeRequest
+	if err := context.ShouldBind(&body); err != nil {
+		writeBindError(context, logger, err)
+		return
+	}
+	promo := &model.PromoCode{
+		Code:           body.Code,
+		Amount:         body.Sum.Decimal(),
+		MaxRedemptions: body.MaxRedemptions,
+		PerUserLimit:   1,
+		StartsAt:       body.StartsAt,
+		EndsAt:         body.EndsAt,
+	}
+	if body.PerUserLimit != nil {
+		promo.PerUserLimit = *body.PerUserLimit
+	}
+	result, err := p.promo.Create(context, promo)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrPromoCodeInvalid):
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrPromoCodeExists):
+			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	response := contracts.NewPromoCodeResponse(result)
+	context.JSON(http.StatusCreated, &response)
+}
+
+// Generate issues a batch of single use gift codes with random values.
+func (p *promoAPI) Generate(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.GiftCodesRequest
+	if err := context.ShouldBind(&body); err != nil {
+		writeBindError(context, logger, err)
+		return
+	}
+	template := &model.PromoCode{
+		Amount:   body.Sum.Decimal(),
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+	}
+	result, err := p.promo.Generate(context, template, body.Prefix, body.Count)
+	if err != nil {
+		if errors.Is(err, model.ErrPromoCodeInvalid) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, contracts.NewPromoCodeResponses(result))
+}
+
+func (p *promoAPI) List(context *gin.Context) {
+	logger := logging.Logger(context)
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", strconv.Itoa(defaultPromoPageSize)))
+	if err != nil || limit <= 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+	result, err := p.promo.List(context, limit, offset)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	context.JSON(http.StatusOK, contracts.NewPromoCodeResponses(result))
+}
@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+const defaultQuarantinePageSize = 100
+
+type QuarantineAPI interface {
+	List(context *gin.Context)
+	Get(context *gin.Context)
+	Review(context *gin.Context)
+}
+
+type quarantineAPI struct {
+	quarantine domain.QuarantineService
+}
+
+func NewQuarantineAPI(quarantine domain.QuarantineService) QuarantineAPI {
+	return &quarantineAPI{quarantine: quarantine}
+}
+
+// List returns the accrual results waiting for review.
+func (q *quarantineAPI) List(context *gin.Context) {
+	logger := logging.Logger(context)
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", strconv.Itoa(defaultQuarantinePageSize)))
+	if err != nil || limit <= 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(context.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+		return
+	}
+	result, err := q.quarantine.List(context, limit, offset)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	context.JSON(http.StatusOK, contracts.NewQuarantineResponses(result))
+}
+
+func (q *quarantineAPI) Get(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, ok := bindQuarantineID(context)
+	if !ok {
+		return
+	}
+	result, err := q.quarantine.Get(context, id)
+	if err != nil {
+		writeQuarantineError(context, logger, err)
+		return
+	}
+	response := contracts.NewQuarantineResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+// Review closes the result with a note, the order is handled by order tracking or as a dead letter.
+func (q *quarantineAPI) Review(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, ok := bindQuarantineID(context)
+	if !ok {
+		return
+	}
+	var body contracts.ReviewQuarantineRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := q.quarantine.Review(context, id, body.Note)
+	if err != nil {
+		writeQuarantineError(context, logger, err)
+		return
+	}
+	response := contracts.NewQuarantineResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func bindQuarantineID(context *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeQuarantineError(context *gin.Context, logger *zap.Logger, err error) {
+	var notFound *domain.ResourceNotFound
+	switch {
+	case errors.As(err, &notFound):
+		context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, model.ErrQuarantineReviewed):
+		context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
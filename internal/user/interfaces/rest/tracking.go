@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/worker"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TrackingAPI interface {
+	Status(context *gin.Context)
+	Pause(context *gin.Context)
+	Resume(context *gin.Context)
+	Trigger(context *gin.Context)
+	Settings(context *gin.Context)
+}
+
+type trackingAPI struct {
+	worker *worker.OrderPooler
+}
+
+func NewTrackingAPI(worker *worker.OrderPooler) TrackingAPI {
+	return &trackingAPI{worker: worker}
+}
+
+// Status shows the worker settings and its latest cycles on this instance.
+func (t *trackingAPI) Status(context *gin.Context) {
+	response := contracts.NewTrackingStatusResponse(t.worker.Status())
+	context.JSON(http.StatusOK, &response)
+}
+
+func (t *trackingAPI) Pause(context *gin.Context) {
+	logging.Logger(context).Info("order tracking paused")
+	t.worker.Pause()
+	t.Status(context)
+}
+
+func (t *trackingAPI) Resume(context *gin.Context) {
+	logging.Logger(context).Info("order tracking resumed")
+	t.worker.Resume()
+	t.Status(context)
+}
+
+// Trigger starts a cycle right away or after the running one, it is refused while tracking is paused
+// or another cycle is queued already.
+func (t *trackingAPI) Trigger(context *gin.Context) {
+	if err := t.worker.Trigger(worker.SourceManual); err != nil {
+		if errors.Is(err, worker.ErrTrackingPaused) || errors.Is(err, worker.ErrCycleRunning) {
+			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusAccepted)
+}
+
+// Settings changes the batch size and the schedule, fields left out keep their value.
+func (t *trackingAPI) Settings(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.TrackingSettingsRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if body.Schedule != nil {
+		if err := t.worker.SetSchedule(*body.Schedule); err != nil {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Info("order tracking schedule changed", zap.String("schedule", *body.Schedule))
+	}
+	if body.BatchSize != nil {
+		if err := t.worker.SetBatchSize(*body.BatchSize); err != nil {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Info("order tracking batch size changed", zap.Int("batch_size", *body.BatchSize))
+	}
+	t.Status(context)
+}
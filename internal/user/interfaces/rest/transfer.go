@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/domain/model"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type TransferAPI interface {
+	Transfer(context *gin.Context)
+}
+
+type transferAPI struct {
+	transfer domain.TransferService
+}
+
+func NewTransferAPI(transfer domain.TransferService) TransferAPI {
+	return &transferAPI{
+		transfer: transfer,
+	}
+}
+
+func (t *transferAPI) Transfer(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.TransferRequest
+	if err := context.ShouldBind(&body); err != nil {
+		writeBindError(context, logger, err)
+		return
+	}
+	result, err := t.transfer.Transfer(context, body.Login, body.Sum.Decimal(), body.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrRecipientNotFound):
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrTransferToSelf), errors.Is(err, model.ErrTransferAmount):
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, model.ErrTransferDailyLimit):
+			context.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, application.ErrNegativeBalance):
+			context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+		default:
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	context.JSON(http.StatusOK, &contracts.TransferResponse{
+		ID:          result.ID,
+		Login:       body.Login,
+		Sum:         result.Amount,
+		Note:        result.Note,
+		ProcessedAt: result.CreatedAt,
+	})
+}
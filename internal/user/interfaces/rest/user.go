@@ -3,6 +3,7 @@ package rest
 import (
 	"errors"
 	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/types"
 	"github.com/DimKa163/gophermart/internal/user/application"
 	"github.com/DimKa163/gophermart/internal/user/domain"
 	"github.com/DimKa163/gophermart/internal/user/domain/model"
@@ -10,6 +11,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultStatementPageSize = 100
+	maxStatementPageSize     = 1000
 )
 
 type UserAPI interface {
@@ -20,17 +28,28 @@ type UserAPI interface {
 	GetBalance(context *gin.Context)
 	Withdraw(context *gin.Context)
 	GetWithdrawals(context *gin.Context)
+	GetTransactions(context *gin.Context)
+	GetStatement(context *gin.Context)
+	GetTier(context *gin.Context)
+	GetTierHistory(context *gin.Context)
 }
 
 type userAPI struct {
-	user  domain.UserService
-	order domain.OrderService
+	user       domain.UserService
+	order      domain.OrderService
+	expiration domain.ExpirationService
+	tier       domain.TierService
 }
 
-func NewUserAPI(user domain.UserService, order domain.OrderService) UserAPI {
+func NewUserAPI(user domain.UserService,
+	order domain.OrderService,
+	expiration domain.ExpirationService,
+	tier domain.TierService) UserAPI {
 	return &userAPI{
-		user:  user,
-		order: order,
+		user:       user,
+		order:      order,
+		expiration: expiration,
+		tier:       tier,
 	}
 }
 
@@ -98,12 +117,16 @@ func (u *userAPI) Upload(context *gin.Context) {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	result, err := u.order.Upload(context, orderID)
+	result, err := u.order.Upload(context, orderID, context.Query("provider"))
 	if err != nil {
 		if errors.Is(err, application.ErrOrderExistsWithAnotherUser) {
 			context.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, model.ErrUnknownProvider) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		logger.Error("unhandled error occurred", zap.Error(err))
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -134,6 +157,7 @@ func (u *userAPI) GetOrders(context *gin.Context) {
 			Accrual:    item.Accrual,
 			Status:     item.Status.String(),
 			UploadedAt: item.UploadedAt,
+			Provider:   item.Provider,
 		}
 	}
 	context.JSON(http.StatusOK, orderItems)
@@ -141,31 +165,70 @@ func (u *userAPI) GetOrders(context *gin.Context) {
 
 func (u *userAPI) GetBalance(context *gin.Context) {
 	logger := logging.Logger(context)
+	if value := context.Query("at"); value != "" {
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := u.user.BalanceAt(context, at)
+		if err != nil {
+			logger.Error("unhandled error occurred", zap.Error(err))
+			context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		context.JSON(http.StatusOK, contracts.NewBalanceAtResponse(result, at))
+		return
+	}
 	result, err := u.user.Balance(context)
 	if err != nil {
 		logger.Error("unhandled error occurred", zap.Error(err))
 		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	context.JSON(http.StatusOK, contracts.BalanceResponse{Current: &result.Current, Withdrawn: &result.Withdrawn})
+	expiring, err := u.expiration.Upcoming(context)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.BalanceResponse{Current: &result.Current, Withdrawn: &result.Withdrawn}
+	for _, item := range expiring {
+		response.Expiring = append(response.Expiring, contracts.ExpiringItem{Sum: item.Amount, ExpiresAt: item.ExpiresAt})
+	}
+	context.JSON(http.StatusOK, response)
 }
 
 func (u *userAPI) Withdraw(context *gin.Context) {
 	logger := logging.Logger(context)
 	var body contracts.WithdrawRequest
 	if err := context.ShouldBind(&body); err != nil {
-		logger.Error("error reading body", zap.Error(err))
 		if errors.Is(err, model.ErrOrderID) {
+			logger.Error("error reading body", zap.Error(err))
 			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 			return
 		}
-		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(context, logger, err)
 		return
 	}
-	err := u.order.Withdraw(context, body.OrderID, body.Sum)
+	var orderTotal *types.Decimal
+	if body.Total != nil {
+		total := body.Total.Decimal()
+		orderTotal = &total
+	}
+	err := u.order.Withdraw(context, body.OrderID, body.Sum.Decimal(), orderTotal)
 	if err != nil {
+		var violation *model.PolicyViolation
+		if errors.As(err, &violation) {
+			context.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "code": violation.Code})
+			return
+		}
+		if errors.Is(err, model.ErrWithdrawalAmount) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		if errors.Is(err, application.ErrNegativeBalance) {
-			context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			context.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error(), "code": model.CodeInsufficientBalance})
 			return
 		}
 		logger.Error("unhandled error occurred", zap.Error(err))
@@ -197,3 +260,102 @@ func (u *userAPI) GetWithdrawals(context *gin.Context) {
 	}
 	context.JSON(http.StatusOK, response)
 }
+
+func (u *userAPI) GetTransactions(context *gin.Context) {
+	logger := logging.Logger(context)
+	result, err := u.user.Transactions(context)
+	if err != nil {
+		logger.Error("Unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	response := make([]contracts.TransactionResponse, len(result))
+	for i, item := range result {
+		response[i] = contracts.NewTransactionResponse(item)
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// GetStatement returns movements of the period from (inclusive) to (exclusive) with a running balance.
+func (u *userAPI) GetStatement(context *gin.Context) {
+	logger := logging.Logger(context)
+	var err error
+	var from time.Time
+	to := time.Now()
+	if value := context.Query("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if value := context.Query("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", strconv.Itoa(defaultStatementPageSize)))
+	if err != nil || limit <= 0 || limit > maxStatementPageSize {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+		return
+	}
+	var cursor *model.StatementCursor
+	if value := context.Query("cursor"); value != "" {
+		if cursor, err = model.ParseStatementCursor(value); err != nil {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	result, err := u.user.Statement(context, from, to, cursor, limit)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidPeriod) || errors.Is(err, model.ErrInvalidCursor) {
+			context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewStatementResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (u *userAPI) GetTier(context *gin.Context) {
+	logger := logging.Logger(context)
+	result, err := u.tier.Progress(context)
+	if err != nil {
+		logger.Error("Unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewTierResponse(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (u *userAPI) GetTierHistory(context *gin.Context) {
+	logger := logging.Logger(context)
+	result, err := u.tier.History(context)
+	if err != nil {
+		logger.Error("Unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	response := make([]contracts.TierHistoryItem, len(result))
+	for i, item := range result {
+		response[i] = contracts.TierHistoryItem{
+			PreviousTierID: item.PreviousTierID,
+			TierID:         item.TierID,
+			RollingAccrued: item.RollingAccrued,
+			ChangedAt:      item.ChangedAt,
+		}
+	}
+	context.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"errors"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/application"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/DimKa163/gophermart/internal/user/interfaces/contracts"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+type WithdrawalAPI interface {
+	GetRules(context *gin.Context)
+	ListOverrides(context *gin.Context)
+	SaveOverride(context *gin.Context)
+	DeleteOverride(context *gin.Context)
+}
+
+type withdrawalAPI struct {
+	policy domain.WithdrawalPolicy
+}
+
+func NewWithdrawalAPI(policy domain.WithdrawalPolicy) WithdrawalAPI {
+	return &withdrawalAPI{
+		policy: policy,
+	}
+}
+
+// GetRules returns the withdrawal rules effective for the current user.
+func (w *withdrawalAPI) GetRules(context *gin.Context) {
+	logger := logging.Logger(context)
+	rules, err := w.policy.Rules(context)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewWithdrawalRulesResponse(rules)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (w *withdrawalAPI) ListOverrides(context *gin.Context) {
+	logger := logging.Logger(context)
+	result, err := w.policy.Overrides(context)
+	if err != nil {
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result) == 0 {
+		context.Status(http.StatusNoContent)
+		return
+	}
+	response := make([]contracts.WithdrawalRuleOverride, len(result))
+	for i, item := range result {
+		response[i] = contracts.NewWithdrawalRuleOverride(item)
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// SaveOverride creates or replaces the override of a user or a tier.
+func (w *withdrawalAPI) SaveOverride(context *gin.Context) {
+	logger := logging.Logger(context)
+	var body contracts.WithdrawalRuleOverride
+	if err := context.ShouldBind(&body); err != nil {
+		logger.Error("error reading body", zap.Error(err))
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	override, err := body.Model()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := w.policy.SaveOverride(context, override)
+	if err != nil {
+		if errors.Is(err, application.ErrWithdrawalRuleScope) {
+			context.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := contracts.NewWithdrawalRuleOverride(result)
+	context.JSON(http.StatusOK, &response)
+}
+
+func (w *withdrawalAPI) DeleteOverride(context *gin.Context) {
+	logger := logging.Logger(context)
+	id, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err = w.policy.DeleteOverride(context, id); err != nil {
+		if errors.Is(err, application.ErrWithdrawalRuleNotFound) {
+			context.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("unhandled error occurred", zap.Error(err))
+		context.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ExpirationJob struct {
+	entryID  cron.EntryID
+	service  domain.ExpirationService
+	schedule string
+	dryRun   bool
+}
+
+func NewExpirationJob(cron *cron.Cron, schedule string, dryRun bool, service domain.ExpirationService) (*ExpirationJob, error) {
+	job := &ExpirationJob{
+		service:  service,
+		schedule: schedule,
+		dryRun:   dryRun,
+	}
+	id, err := cron.AddFunc(schedule, job.run)
+	if err != nil {
+		return nil, err
+	}
+	job.entryID = id
+	return job, nil
+}
+
+func (j *ExpirationJob) run() {
+	ctx := context.Background()
+	logger := logging.Logger(ctx).With(zap.String("schedule", j.schedule), zap.Bool("dryRun", j.dryRun))
+	logger.Info("start expiring points")
+	report, err := j.service.Expire(ctx, time.Now(), j.dryRun)
+	if err != nil {
+		logger.Warn("Failed to expire points", zap.Error(err))
+		return
+	}
+	logger.Info("points expired",
+		zap.Int("lots", len(report.Items)),
+		zap.String("total", report.Total.String()))
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ExportJob struct {
+	entryID  cron.EntryID
+	service  domain.ExportService
+	schedule string
+}
+
+func NewExportJob(cron *cron.Cron, schedule string, service domain.ExportService) (*ExportJob, error) {
+	job := &ExportJob{
+		service:  service,
+		schedule: schedule,
+	}
+	id, err := cron.AddFunc(schedule, job.run)
+	if err != nil {
+		return nil, err
+	}
+	job.entryID = id
+	return job, nil
+}
+
+func (j *ExportJob) run() {
+	ctx := context.Background()
+	logger := logging.Logger(ctx).With(zap.String("schedule", j.schedule))
+	completed, err := j.service.Process(ctx)
+	if err != nil {
+		logger.Warn("Failed to process exports", zap.Error(err))
+	}
+	if completed > 0 {
+		logger.Info("exports generated", zap.Int("count", completed))
+	}
+	removed, err := j.service.Cleanup(ctx, time.Now())
+	if err != nil {
+		logger.Warn("Failed to clean up exports", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		logger.Info("expired exports removed", zap.Int("count", removed))
+	}
+}
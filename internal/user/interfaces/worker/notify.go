@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/db"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// UploadNotifier starts order tracking as soon as orders are uploaded instead of waiting for the schedule.
+// Notifications arriving within the debounce window are coalesced into one cycle.
+type UploadNotifier struct {
+	listener *db.Listener
+	pooler   *OrderPooler
+	debounce time.Duration
+	mu       sync.Mutex
+	pending  bool
+	received int
+}
+
+func NewUploadNotifier(listener *db.Listener, pooler *OrderPooler, debounce time.Duration) *UploadNotifier {
+	return &UploadNotifier{listener: listener, pooler: pooler, debounce: debounce}
+}
+
+func (n *UploadNotifier) Run(ctx context.Context) {
+	n.listener.Listen(ctx, func(string) {
+		n.notify(ctx)
+	})
+}
+
+func (n *UploadNotifier) notify(ctx context.Context) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.received++
+	if n.pending {
+		return
+	}
+	n.pending = true
+	time.AfterFunc(n.debounce, func() {
+		n.mu.Lock()
+		received := n.received
+		n.pending = false
+		n.received = 0
+		n.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		logging.Logger(ctx).Debug("orders uploaded, triggering tracking", zap.Int("orders", received))
+		_ = n.pooler.Trigger(SourceUpload)
+	})
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/user/domain"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"time"
+)
+
+type SnapshotJob struct {
+	entryID  cron.EntryID
+	service  domain.BalanceService
+	schedule string
+}
+
+func NewSnapshotJob(cron *cron.Cron, schedule string, service domain.BalanceService) (*SnapshotJob, error) {
+	job := &SnapshotJob{
+		service:  service,
+		schedule: schedule,
+	}
+	id, err := cron.AddFunc(schedule, job.run)
+	if err != nil {
+		return nil, err
+	}
+	job.entryID = id
+	return job, nil
+}
+
+func (j *SnapshotJob) run() {
+	ctx := context.Background()
+	// snapshots end at the start of the UTC day, movements of that moment are long committed
+	cutoff := time.Now().UTC().Truncate(24 * time.Hour)
+	logger := logging.Logger(ctx).With(zap.String("schedule", j.schedule), zap.Time("cutoff", cutoff))
+	count, err := j.service.Snapshot(ctx, cutoff)
+	if err != nil {
+		logger.Warn("Failed to snapshot balances", zap.Error(err))
+		return
+	}
+	logger.Info("balances snapshotted", zap.Int64("users", count))
+}
@@ -2,62 +2,273 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/DimKa163/gophermart/internal/shared/db"
 	"github.com/DimKa163/gophermart/internal/shared/logging"
+	"github.com/DimKa163/gophermart/internal/shared/tripper"
 	"github.com/DimKa163/gophermart/internal/user/application"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+var (
+	ErrTrackingPaused  = errors.New("order tracking is paused")
+	ErrCycleRunning    = errors.New("a tracking cycle is already running and another one is queued")
+	ErrTrackingStopped = errors.New("order tracking is stopped")
+)
+
+// Cycle sources tell what started a tracking cycle.
+const (
+	SourceSchedule = "schedule"
+	SourceUpload   = "upload"
+	SourceManual   = "manual"
+)
+
+// Cycle is the outcome of one tracking run.
+type Cycle struct {
+	Source    string
+	StartedAt time.Time
+	Duration  time.Duration
+	application.TrackOrderReport
+	Error string
+}
+
+// WorkerStatus is a snapshot of the worker settings and its latest cycles, the newest first.
+type WorkerStatus struct {
+	Paused    bool
+	Running   bool
+	Schedule  string
+	BatchSize int
+	Cycles    []Cycle
+}
+
+// OrderPooler runs order tracking on a schedule. Pausing, the batch size and the schedule can be changed
+// at runtime, they only apply to this instance and are reset on restart.
 type OrderPooler struct {
-	entryID  cron.EntryID
-	signal   chan struct{}
+	cron     *cron.Cron
+	signal   chan string
 	handler  *application.TrackOrderHandler
+	breakers []*tripper.CircuitBreaker
+	leader   *db.LeaderElector
+	paused   atomic.Bool
+	running  atomic.Bool
+	limit    atomic.Int64
+	mu       sync.Mutex
+	entryID  cron.EntryID
 	schedule string
-	limit    int
+	history  int
+	cycles   []Cycle
+	started  atomic.Bool
+	stop     sync.Once
+	done     chan struct{}
+	stopped  chan struct{}
+	cancel   context.CancelFunc
+}
+
+// NewWorker creates the order tracking worker, it only tracks orders while the instance holds the leader role
+// and at least one accrual provider takes requests. The last history cycles are kept for the status.
+func NewWorker(cron *cron.Cron, schedule string, limit int, history int, handler *application.TrackOrderHandler,
+	breakers []*tripper.CircuitBreaker, leader *db.LeaderElector) (*OrderPooler, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("order batch size must be positive, got %d", limit)
+	}
+	// one trigger is kept while a cycle runs, so orders uploaded meanwhile are picked up right after it
+	w := &OrderPooler{
+		cron:     cron,
+		signal:   make(chan string, 1),
+		handler:  handler,
+		breakers: breakers,
+		leader:   leader,
+		history:  history,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	w.limit.Store(int64(limit))
+	if err := w.SetSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Trigger asks for a cycle right away. While a cycle runs one more is queued, further triggers are dropped
+// until it starts, and so are triggers of a stopped worker.
+func (w *OrderPooler) Trigger(source string) error {
+	if w.paused.Load() {
+		return ErrTrackingPaused
+	}
+	select {
+	case <-w.done:
+		return ErrTrackingStopped
+	default:
+	}
+	select {
+	case w.signal <- source:
+		return nil
+	default:
+		return ErrCycleRunning
+	}
+}
+
+// Pause skips every cycle until Resume, a running cycle is finished.
+func (w *OrderPooler) Pause() {
+	w.paused.Store(true)
+}
+
+func (w *OrderPooler) Resume() {
+	w.paused.Store(false)
+}
+
+func (w *OrderPooler) SetBatchSize(limit int) error {
+	if limit < 1 {
+		return fmt.Errorf("order batch size must be positive, got %d", limit)
+	}
+	w.limit.Store(int64(limit))
+	return nil
 }
 
-func NewWorker(cron *cron.Cron, schedule string, limit int, handler *application.TrackOrderHandler) (*OrderPooler, error) {
-	signal := make(chan struct{})
-	id, err := cron.AddFunc(schedule, func() {
-		signal <- struct{}{}
+// SetSchedule replaces the cron schedule, the old one stays in place when the new one is invalid.
+func (w *OrderPooler) SetSchedule(schedule string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id, err := w.cron.AddFunc(schedule, func() {
+		// a scheduled run never waits for the running cycle, otherwise stopping cron would wait for it as well
+		_ = w.Trigger(SourceSchedule)
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &OrderPooler{
-		entryID:  id,
-		signal:   signal,
-		schedule: schedule,
-		limit:    limit,
-		handler:  handler,
-	}, nil
+	if w.entryID != 0 {
+		w.cron.Remove(w.entryID)
+	}
+	w.entryID, w.schedule = id, schedule
+	return nil
 }
 
-func (w *OrderPooler) Run(ctx context.Context) error {
+func (w *OrderPooler) Status() WorkerStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return WorkerStatus{
+		Paused:    w.paused.Load(),
+		Running:   w.running.Load(),
+		Schedule:  w.schedule,
+		BatchSize: int(w.limit.Load()),
+		Cycles:    append([]Cycle(nil), w.cycles...),
+	}
+}
 
+func (w *OrderPooler) record(cycle Cycle) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cycles = append([]Cycle{cycle}, w.cycles...)
+	if len(w.cycles) > w.history {
+		w.cycles = w.cycles[:w.history]
+	}
+}
+
+// Run waits for cycles in the background until ctx is done or the worker is stopped.
+func (w *OrderPooler) Run(ctx context.Context) error {
+	if !w.started.CompareAndSwap(false, true) {
+		return errors.New("order tracking is already running")
+	}
 	logger := logging.Logger(ctx)
-	ctx = logging.SetLogger(ctx, logger.With(zap.String("schedule", w.schedule)))
+	ctx, cancel := context.WithCancel(ctx)
+	w.mu.Lock()
+	w.cancel = cancel
+	w.mu.Unlock()
 	go func() {
+		defer close(w.stopped)
+		defer cancel()
 		for {
 			select {
 			case <-ctx.Done():
-			case <-w.signal:
-				logger := logging.Logger(ctx)
-				logger.Info("start processing orders")
-				err := w.handler.Handle(ctx, &application.TrackOrderCommand{
-					Limit: 100,
-				})
-				if err != nil {
-					logger.Warn("Failed to handle orders", zap.Error(err))
+				return
+			case <-w.done:
+				return
+			case source := <-w.signal:
+				// a trigger queued before the worker was stopped is dropped
+				select {
+				case <-w.done:
+					return
+				default:
 				}
+				w.cycle(ctx, source)
 			}
 		}
 	}()
 	logger.Info("started")
 	return nil
 }
-func (w *OrderPooler) Stop(ctx context.Context) {
+
+func (w *OrderPooler) cycle(ctx context.Context, source string) {
+	logger := logging.Logger(ctx).With(zap.String("source", source))
+	if w.paused.Load() {
+		logger.Debug("order tracking is paused, skipping cycle")
+		return
+	}
+	if !w.leader.IsLeader() {
+		logger.Debug("not the leader, skipping cycle")
+		return
+	}
+	if retryAt, open := w.allOpen(); open {
+		logger.Warn("accrual circuit breakers are open, skipping cycle",
+			zap.Time("retry_at", retryAt))
+		return
+	}
+	logger.Info("start processing orders")
+	w.running.Store(true)
+	defer w.running.Store(false)
+	cycle := Cycle{Source: source, StartedAt: time.Now()}
+	report, err := w.handler.Handle(ctx, &application.TrackOrderCommand{
+		Limit: int(w.limit.Load()),
+	})
+	cycle.Duration = time.Since(cycle.StartedAt)
+	cycle.TrackOrderReport = report
+	if err != nil {
+		cycle.Error = err.Error()
+		logger.Warn("Failed to handle orders", zap.Error(err))
+	}
+	w.record(cycle)
+}
+
+// allOpen reports whether every circuit breaker is open and when the first of them lets a probe through.
+func (w *OrderPooler) allOpen() (time.Time, bool) {
+	var retryAt time.Time
+	for _, breaker := range w.breakers {
+		status := breaker.Status()
+		if status.State != tripper.BreakerOpen {
+			return time.Time{}, false
+		}
+		if retryAt.IsZero() || status.RetryAt.Before(retryAt) {
+			retryAt = status.RetryAt
+		}
+	}
+	return retryAt, len(w.breakers) > 0
+}
+
+// Stop refuses new cycles and waits for the running one. When ctx is done first the cycle is cancelled,
+// its open transaction is rolled back and the claimed orders return to the queue once their lease expires.
+func (w *OrderPooler) Stop(ctx context.Context) error {
 	logger := logging.Logger(ctx)
 	logger.Info("stopping processing orders")
-	close(w.signal)
+	w.stop.Do(func() {
+		close(w.done)
+	})
+	if !w.started.Load() {
+		return nil
+	}
+	select {
+	case <-w.stopped:
+		return nil
+	case <-ctx.Done():
+		logger.Warn("tracking cycle did not finish in time, cancelling it")
+		w.mu.Lock()
+		w.cancel()
+		w.mu.Unlock()
+		<-w.stopped
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,18 @@
+package worker
+
+import (
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTriggerDuringCycleShouldQueueOneMore(t *testing.T) {
+	// without Run nothing takes the signal, just like while a cycle is running
+	w, err := NewWorker(cron.New(), "@every 1h", 10, 5, nil, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, w.Trigger(SourceUpload), "the first trigger should be queued")
+	assert.ErrorIs(t, w.Trigger(SourceUpload), ErrCycleRunning, "only one trigger should be queued")
+}
@@ -35,6 +35,26 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Claim mocks base method.
+func (m *MockOrderRepository) Claim(ctx context.Context, lease model.Lease, limit int, status ...model.OrderStatus) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, lease, limit}
+	for _, a := range status {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Claim", varargs...)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockOrderRepositoryMockRecorder) Claim(ctx, lease, limit interface{}, status ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, lease, limit}, status...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockOrderRepository)(nil).Claim), varargs...)
+}
+
 // Exists mocks base method.
 func (m *MockOrderRepository) Exists(ctx context.Context, id model.OrderID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -80,24 +100,34 @@ func (mr *MockOrderRepositoryMockRecorder) GetAll(ctx, userID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrderRepository)(nil).GetAll), ctx, userID)
 }
 
-// GetForUpdate mocks base method.
-func (m *MockOrderRepository) GetForUpdate(ctx context.Context, limit, offset int, status ...model.OrderStatus) ([]*model.Order, error) {
+// GetDeadLettered mocks base method.
+func (m *MockOrderRepository) GetDeadLettered(ctx context.Context, limit, offset int) ([]*model.Order, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, limit, offset}
-	for _, a := range status {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "GetForUpdate", varargs...)
+	ret := m.ctrl.Call(m, "GetDeadLettered", ctx, limit, offset)
 	ret0, _ := ret[0].([]*model.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetDeadLettered indicates an expected call of GetDeadLettered.
+func (mr *MockOrderRepositoryMockRecorder) GetDeadLettered(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLettered", reflect.TypeOf((*MockOrderRepository)(nil).GetDeadLettered), ctx, limit, offset)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockOrderRepository) GetForUpdate(ctx context.Context, id model.OrderID) (*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // GetForUpdate indicates an expected call of GetForUpdate.
-func (mr *MockOrderRepositoryMockRecorder) GetForUpdate(ctx, limit, offset interface{}, status ...interface{}) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) GetForUpdate(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, limit, offset}, status...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockOrderRepository)(nil).GetForUpdate), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockOrderRepository)(nil).GetForUpdate), ctx, id)
 }
 
 // Insert mocks base method.
@@ -115,6 +145,34 @@ func (mr *MockOrderRepositoryMockRecorder) Insert(ctx, order interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockOrderRepository)(nil).Insert), ctx, order)
 }
 
+// NotifyUploaded mocks base method.
+func (m *MockOrderRepository) NotifyUploaded(ctx context.Context, id model.OrderID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyUploaded", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyUploaded indicates an expected call of NotifyUploaded.
+func (mr *MockOrderRepositoryMockRecorder) NotifyUploaded(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyUploaded", reflect.TypeOf((*MockOrderRepository)(nil).NotifyUploaded), ctx, id)
+}
+
+// Release mocks base method.
+func (m *MockOrderRepository) Release(ctx context.Context, ids []model.OrderID, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, ids, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockOrderRepositoryMockRecorder) Release(ctx, ids, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockOrderRepository)(nil).Release), ctx, ids, owner)
+}
+
 // Update mocks base method.
 func (m *MockOrderRepository) Update(ctx context.Context, order *model.Order) error {
 	m.ctrl.T.Helper()
@@ -128,3 +186,45 @@ func (mr *MockOrderRepositoryMockRecorder) Update(ctx, order interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrderRepository)(nil).Update), ctx, order)
 }
+
+// UpdateChecked mocks base method.
+func (m *MockOrderRepository) UpdateChecked(ctx context.Context, order *model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChecked", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChecked indicates an expected call of UpdateChecked.
+func (mr *MockOrderRepositoryMockRecorder) UpdateChecked(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChecked", reflect.TypeOf((*MockOrderRepository)(nil).UpdateChecked), ctx, order)
+}
+
+// UpdateDeadLettered mocks base method.
+func (m *MockOrderRepository) UpdateDeadLettered(ctx context.Context, order *model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDeadLettered", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDeadLettered indicates an expected call of UpdateDeadLettered.
+func (mr *MockOrderRepositoryMockRecorder) UpdateDeadLettered(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeadLettered", reflect.TypeOf((*MockOrderRepository)(nil).UpdateDeadLettered), ctx, order)
+}
+
+// UpdateLeased mocks base method.
+func (m *MockOrderRepository) UpdateLeased(ctx context.Context, order *model.Order, lease model.Lease) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLeased", ctx, order, lease)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLeased indicates an expected call of UpdateLeased.
+func (mr *MockOrderRepositoryMockRecorder) UpdateLeased(ctx, order, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLeased", reflect.TypeOf((*MockOrderRepository)(nil).UpdateLeased), ctx, order, lease)
+}
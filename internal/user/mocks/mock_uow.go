@@ -78,6 +78,34 @@ func (mr *MockUnitOfWorkMockRecorder) BonusMovementRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BonusMovementRepository", reflect.TypeOf((*MockUnitOfWork)(nil).BonusMovementRepository))
 }
 
+// ExportRepository mocks base method.
+func (m *MockUnitOfWork) ExportRepository() repository.ExportRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportRepository")
+	ret0, _ := ret[0].(repository.ExportRepository)
+	return ret0
+}
+
+// ExportRepository indicates an expected call of ExportRepository.
+func (mr *MockUnitOfWorkMockRecorder) ExportRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportRepository", reflect.TypeOf((*MockUnitOfWork)(nil).ExportRepository))
+}
+
+// LotRepository mocks base method.
+func (m *MockUnitOfWork) LotRepository() repository.LotRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LotRepository")
+	ret0, _ := ret[0].(repository.LotRepository)
+	return ret0
+}
+
+// LotRepository indicates an expected call of LotRepository.
+func (mr *MockUnitOfWorkMockRecorder) LotRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LotRepository", reflect.TypeOf((*MockUnitOfWork)(nil).LotRepository))
+}
+
 // OrderRepository mocks base method.
 func (m *MockUnitOfWork) OrderRepository() repository.OrderRepository {
 	m.ctrl.T.Helper()
@@ -92,6 +120,62 @@ func (mr *MockUnitOfWorkMockRecorder) OrderRepository() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderRepository", reflect.TypeOf((*MockUnitOfWork)(nil).OrderRepository))
 }
 
+// PromoCodeRepository mocks base method.
+func (m *MockUnitOfWork) PromoCodeRepository() repository.PromoCodeRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoCodeRepository")
+	ret0, _ := ret[0].(repository.PromoCodeRepository)
+	return ret0
+}
+
+// PromoCodeRepository indicates an expected call of PromoCodeRepository.
+func (mr *MockUnitOfWorkMockRecorder) PromoCodeRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoCodeRepository", reflect.TypeOf((*MockUnitOfWork)(nil).PromoCodeRepository))
+}
+
+// QuarantineRepository mocks base method.
+func (m *MockUnitOfWork) QuarantineRepository() repository.QuarantineRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantineRepository")
+	ret0, _ := ret[0].(repository.QuarantineRepository)
+	return ret0
+}
+
+// QuarantineRepository indicates an expected call of QuarantineRepository.
+func (mr *MockUnitOfWorkMockRecorder) QuarantineRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantineRepository", reflect.TypeOf((*MockUnitOfWork)(nil).QuarantineRepository))
+}
+
+// TierRepository mocks base method.
+func (m *MockUnitOfWork) TierRepository() repository.TierRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TierRepository")
+	ret0, _ := ret[0].(repository.TierRepository)
+	return ret0
+}
+
+// TierRepository indicates an expected call of TierRepository.
+func (mr *MockUnitOfWorkMockRecorder) TierRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TierRepository", reflect.TypeOf((*MockUnitOfWork)(nil).TierRepository))
+}
+
+// TransferRepository mocks base method.
+func (m *MockUnitOfWork) TransferRepository() repository.TransferRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferRepository")
+	ret0, _ := ret[0].(repository.TransferRepository)
+	return ret0
+}
+
+// TransferRepository indicates an expected call of TransferRepository.
+func (mr *MockUnitOfWorkMockRecorder) TransferRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferRepository", reflect.TypeOf((*MockUnitOfWork)(nil).TransferRepository))
+}
+
 // UserRepository mocks base method.
 func (m *MockUnitOfWork) UserRepository() repository.UserRepository {
 	m.ctrl.T.Helper()
@@ -105,3 +189,17 @@ func (mr *MockUnitOfWorkMockRecorder) UserRepository() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRepository", reflect.TypeOf((*MockUnitOfWork)(nil).UserRepository))
 }
+
+// WithdrawalRuleRepository mocks base method.
+func (m *MockUnitOfWork) WithdrawalRuleRepository() repository.WithdrawalRuleRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawalRuleRepository")
+	ret0, _ := ret[0].(repository.WithdrawalRuleRepository)
+	return ret0
+}
+
+// WithdrawalRuleRepository indicates an expected call of WithdrawalRuleRepository.
+func (mr *MockUnitOfWorkMockRecorder) WithdrawalRuleRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawalRuleRepository", reflect.TypeOf((*MockUnitOfWork)(nil).WithdrawalRuleRepository))
+}
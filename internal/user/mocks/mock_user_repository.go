@@ -35,6 +35,21 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Exists mocks base method.
+func (m *MockUserRepository) Exists(ctx context.Context, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockUserRepositoryMockRecorder) Exists(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockUserRepository)(nil).Exists), ctx, userID)
+}
+
 // Get mocks base method.
 func (m *MockUserRepository) Get(ctx context.Context, login string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +95,20 @@ func (mr *MockUserRepositoryMockRecorder) Insert(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockUserRepository)(nil).Insert), ctx, user)
 }
 
+// Lock mocks base method.
+func (m *MockUserRepository) Lock(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockUserRepositoryMockRecorder) Lock(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockUserRepository)(nil).Lock), ctx, userID)
+}
+
 // LoginExists mocks base method.
 func (m *MockUserRepository) LoginExists(ctx context.Context, login string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,18 @@
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type = 0 THEN amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type = 0 THEN amount WHEN type = 1 THEN -amount ELSE 0 END) as current
+FROM transactions
+GROUP BY user_id;
+
+DROP INDEX IF EXISTS transactions_order_id_ix;
+
+DROP INDEX IF EXISTS transactions_reference_id_uix;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS reason;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS reference_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS id;
@@ -0,0 +1,18 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS id BIGSERIAL PRIMARY KEY;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reference_id BIGINT NULL REFERENCES transactions(id);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reason VARCHAR(32) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_reference_id_uix ON transactions(reference_id);
+
+CREATE INDEX IF NOT EXISTS transactions_order_id_ix ON transactions(order_id ASC);
+
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type = 0 THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2) THEN amount WHEN type IN (1, 3) THEN -amount ELSE 0 END) as current
+FROM transactions
+GROUP BY user_id;
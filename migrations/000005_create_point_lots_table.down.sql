@@ -0,0 +1,22 @@
+DROP VIEW IF EXISTS bonus_balances;
+
+CREATE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type = 0 THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2) THEN amount WHEN type IN (1, 3) THEN -amount ELSE 0 END) as current
+FROM transactions
+GROUP BY user_id;
+
+DELETE FROM transactions WHERE type = 4;
+
+DROP INDEX IF EXISTS transactions_reference_id_uix;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_reference_id_uix ON transactions(reference_id);
+
+DROP INDEX IF EXISTS point_lots_created_at_ix;
+
+DROP INDEX IF EXISTS point_lots_user_id_ix;
+
+DROP TABLE IF EXISTS point_lots;
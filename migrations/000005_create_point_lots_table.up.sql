@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS point_lots
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    remaining DECIMAL(10, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS point_lots_user_id_ix ON point_lots(user_id ASC, created_at ASC) WHERE remaining > 0;
+
+CREATE INDEX IF NOT EXISTS point_lots_created_at_ix ON point_lots(created_at ASC) WHERE remaining > 0;
+
+INSERT INTO point_lots (created_at, user_id, transaction_id, amount, remaining)
+SELECT c.created_at, c.user_id, c.id, c.amount,
+       GREATEST(0, LEAST(c.amount, c.running - COALESCE(d.debited, 0)))
+FROM (SELECT id, created_at, user_id, amount,
+             SUM(amount) OVER (PARTITION BY user_id ORDER BY created_at, id) AS running
+      FROM transactions WHERE type IN (0, 2)) c
+LEFT JOIN (SELECT user_id, SUM(amount) AS debited FROM transactions WHERE type IN (1, 3) GROUP BY user_id) d
+    ON d.user_id = c.user_id;
+
+DROP INDEX IF EXISTS transactions_reference_id_uix;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_reference_id_uix ON transactions(reference_id) WHERE type IN (2, 3);
+
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type = 0 THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2) THEN amount WHEN type IN (1, 3, 4) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
@@ -0,0 +1,21 @@
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type = 0 THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2) THEN amount WHEN type IN (1, 3, 4) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
+
+DROP INDEX IF EXISTS transactions_user_id_created_at_ix;
+
+DROP INDEX IF EXISTS tier_history_user_id_ix;
+
+DROP TABLE IF EXISTS tier_history;
+
+ALTER TABLE users DROP COLUMN IF EXISTS tier_id;
+
+DROP INDEX IF EXISTS tiers_name_uix;
+
+DROP TABLE IF EXISTS tiers;
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS tiers
+(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(25) NOT NULL,
+    threshold DECIMAL(10, 2) NOT NULL,
+    multiplier DECIMAL(4, 2) NOT NULL DEFAULT 1,
+    perks TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tiers_name_uix ON tiers(name);
+
+INSERT INTO tiers (name, threshold, multiplier, perks) VALUES
+    ('Silver', 1000, 1.05, '{"priority support"}'),
+    ('Gold', 5000, 1.10, '{"priority support", "free delivery"}'),
+    ('Platinum', 15000, 1.25, '{"priority support", "free delivery", "personal manager"}')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tier_id INT NULL REFERENCES tiers(id);
+
+CREATE TABLE IF NOT EXISTS tier_history
+(
+    id BIGSERIAL PRIMARY KEY,
+    changed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    previous_tier_id INT NULL REFERENCES tiers(id),
+    tier_id INT NULL REFERENCES tiers(id),
+    rolling_accrued DECIMAL(10, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tier_history_user_id_ix ON tier_history(user_id ASC, changed_at ASC);
+
+CREATE INDEX IF NOT EXISTS transactions_user_id_created_at_ix ON transactions(user_id ASC, created_at ASC);
+
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type IN (0, 5) THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2, 5) THEN amount WHEN type IN (1, 3, 4) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
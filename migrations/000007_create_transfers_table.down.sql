@@ -0,0 +1,21 @@
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type IN (0, 5) THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2, 5) THEN amount WHEN type IN (1, 3, 4) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
+
+DELETE FROM point_lots WHERE transaction_id IN (SELECT id FROM transactions WHERE transfer_id IS NOT NULL);
+
+DELETE FROM transactions WHERE transfer_id IS NOT NULL;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+
+ALTER TABLE transactions ALTER COLUMN order_id SET NOT NULL;
+
+DROP INDEX IF EXISTS transfers_sender_id_ix;
+
+DROP TABLE IF EXISTS transfers;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS transfers
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    sender_id BIGINT NOT NULL REFERENCES users(id),
+    recipient_id BIGINT NOT NULL REFERENCES users(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    note VARCHAR(140) NULL
+);
+
+CREATE INDEX IF NOT EXISTS transfers_sender_id_ix ON transfers(sender_id ASC, created_at ASC);
+
+ALTER TABLE transactions ALTER COLUMN order_id DROP DEFAULT;
+
+ALTER TABLE transactions ALTER COLUMN order_id DROP NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS transfer_id BIGINT NULL REFERENCES transfers(id);
+
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type IN (0, 5) THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2, 5, 7) THEN amount WHEN type IN (1, 3, 4, 6) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
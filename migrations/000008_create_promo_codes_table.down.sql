@@ -0,0 +1,21 @@
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type IN (0, 5) THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2, 5, 7) THEN amount WHEN type IN (1, 3, 4, 6) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
+
+DROP INDEX IF EXISTS promo_redemptions_code_user_ix;
+
+DROP TABLE IF EXISTS promo_redemptions;
+
+DELETE FROM point_lots WHERE transaction_id IN (SELECT id FROM transactions WHERE type = 8);
+
+DELETE FROM transactions WHERE type = 8;
+
+DROP INDEX IF EXISTS promo_codes_code_uix;
+
+DROP TABLE IF EXISTS promo_codes;
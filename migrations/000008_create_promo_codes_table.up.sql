@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS promo_codes
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    code VARCHAR(32) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    max_redemptions INT NOT NULL DEFAULT 0,
+    per_user_limit INT NOT NULL DEFAULT 1,
+    starts_at TIMESTAMPTZ NULL,
+    ends_at TIMESTAMPTZ NULL,
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    redemptions INT NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS promo_codes_code_uix ON promo_codes(code);
+
+CREATE TABLE IF NOT EXISTS promo_redemptions
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    promo_code_id BIGINT NOT NULL REFERENCES promo_codes(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id)
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_code_user_ix ON promo_redemptions(promo_code_id ASC, user_id ASC);
+
+CREATE OR REPLACE VIEW bonus_balances AS
+SELECT
+    user_id,
+    SUM(CASE WHEN type IN (0, 5) THEN amount WHEN type = 3 THEN -amount ELSE 0 END) AS accrued,
+    SUM(CASE WHEN type = 1 THEN amount WHEN type = 2 THEN -amount ELSE 0 END) AS withdrawn,
+    SUM(CASE WHEN type IN (0, 2, 5, 7, 8) THEN amount WHEN type IN (1, 3, 4, 6) THEN -amount ELSE 0 END) as current,
+    SUM(CASE WHEN type = 4 THEN amount ELSE 0 END) AS expired
+FROM transactions
+GROUP BY user_id;
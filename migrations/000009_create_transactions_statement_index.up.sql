@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS transactions_user_id_created_at_id_ix ON transactions(user_id ASC, created_at ASC, id ASC);
+
+DROP INDEX IF EXISTS transactions_user_id_created_at_ix;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS exports_pending_ix;
+
+DROP INDEX IF EXISTS exports_user_id_ix;
+
+DROP TABLE IF EXISTS exports;
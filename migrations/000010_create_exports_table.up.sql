@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS exports
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    format VARCHAR(8) NOT NULL,
+    period_from TIMESTAMPTZ NOT NULL,
+    period_to TIMESTAMPTZ NOT NULL,
+    types SMALLINT[] NULL,
+    status SMALLINT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    done INT NOT NULL DEFAULT 0,
+    file VARCHAR(255) NULL,
+    error TEXT NULL,
+    claimed_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    expires_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS exports_user_id_ix ON exports(user_id ASC);
+
+CREATE INDEX IF NOT EXISTS exports_pending_ix ON exports(status ASC, id ASC) WHERE status IN (0, 1);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS withdrawal_rules_tier_id_uix;
+
+DROP INDEX IF EXISTS withdrawal_rules_user_id_uix;
+
+DROP TABLE IF EXISTS withdrawal_rules;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS withdrawal_rules
+(
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NULL REFERENCES users(id),
+    tier_id INT NULL REFERENCES tiers(id),
+    min_amount DECIMAL(10, 2) NULL,
+    max_amount DECIMAL(10, 2) NULL,
+    daily_cap DECIMAL(10, 2) NULL,
+    monthly_cap DECIMAL(10, 2) NULL,
+    max_order_share DECIMAL(5, 4) NULL,
+    cooling_off_seconds BIGINT NULL,
+    CONSTRAINT withdrawal_rules_scope_chk CHECK ((user_id IS NULL) <> (tier_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS withdrawal_rules_user_id_uix ON withdrawal_rules(user_id) WHERE user_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS withdrawal_rules_tier_id_uix ON withdrawal_rules(tier_id) WHERE tier_id IS NOT NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS balance_snapshots
+(
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    taken_at TIMESTAMPTZ NOT NULL,
+    accrued DECIMAL(12, 2) NOT NULL,
+    withdrawn DECIMAL(12, 2) NOT NULL,
+    current DECIMAL(12, 2) NOT NULL,
+    expired DECIMAL(12, 2) NOT NULL,
+    PRIMARY KEY (user_id, taken_at)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS leased_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS leased_until;
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS leased_until TIMESTAMPTZ NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS leased_by VARCHAR(128) NULL;
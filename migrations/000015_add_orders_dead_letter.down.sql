@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS orders_dead_lettered_at_ix;
+
+DROP INDEX IF EXISTS orders_next_check_at_ix;
+CREATE INDEX IF NOT EXISTS orders_next_check_at_ix ON orders(next_check_at ASC) WHERE status IN (0, 1);
+
+ALTER TABLE orders DROP COLUMN IF EXISTS dead_lettered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS error_class;
+ALTER TABLE orders DROP COLUMN IF EXISTS last_error;
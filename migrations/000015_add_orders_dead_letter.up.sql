@@ -0,0 +1,8 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS last_error TEXT NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS error_class VARCHAR(32) NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMPTZ NULL;
+
+DROP INDEX IF EXISTS orders_next_check_at_ix;
+CREATE INDEX IF NOT EXISTS orders_next_check_at_ix ON orders(next_check_at ASC) WHERE status IN (0, 1) AND dead_lettered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS orders_dead_lettered_at_ix ON orders(dead_lettered_at ASC) WHERE dead_lettered_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS provider VARCHAR(64) NULL;
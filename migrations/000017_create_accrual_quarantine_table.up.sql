@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS accrual_quarantine
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    order_id BIGINT NOT NULL REFERENCES orders(id),
+    provider VARCHAR(64) NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    reported_number TEXT NOT NULL,
+    reported_status TEXT NOT NULL,
+    reported_accrual NUMERIC NULL,
+    reviewed_at TIMESTAMPTZ NULL,
+    review_note TEXT NULL
+);
+
+CREATE INDEX IF NOT EXISTS accrual_quarantine_pending_ix ON accrual_quarantine(created_at DESC) WHERE reviewed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS accrual_quarantine_order_id_ix ON accrual_quarantine(order_id ASC);
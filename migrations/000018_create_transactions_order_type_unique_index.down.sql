@@ -0,0 +1 @@
+DROP INDEX IF EXISTS transactions_order_id_type_uix;
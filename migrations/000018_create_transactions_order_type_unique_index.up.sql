@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transactions WHERE type IN (0, 5) AND order_id IS NOT NULL
+               GROUP BY order_id, type HAVING COUNT(*) > 1) THEN
+        RAISE EXCEPTION 'orders credited more than once with ACCRUAL or BONUS transactions, resolve them before migrating';
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_order_id_type_uix ON transactions(order_id, type) WHERE type IN (0, 5);